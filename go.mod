@@ -3,6 +3,7 @@ module github.com/bernos/conceptmapper
 go 1.19
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/gosimple/slug v1.13.1
 	github.com/urfave/cli/v2 v2.25.3
 	gopkg.in/yaml.v3 v3.0.1
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/bernos/conceptmapper/pkg/cxl"
+	"github.com/bernos/conceptmapper/pkg/cypher"
+	"github.com/bernos/conceptmapper/pkg/diagrams"
+	"github.com/bernos/conceptmapper/pkg/lsp"
+	"github.com/bernos/conceptmapper/pkg/network"
+	"github.com/bernos/conceptmapper/pkg/rdf"
 	"github.com/bernos/conceptmapper/pkg/sitegenerator"
 	"github.com/urfave/cli/v2"
 )
@@ -27,6 +36,45 @@ func main() {
 						Aliases: []string{"o"},
 						Usage:   "Output markdown site to this dir",
 					},
+					&cli.StringFlag{
+						Name:  "flavour",
+						Value: "mkdocs",
+						Usage: "Lay out the site for mkdocs, hugo or docusaurus",
+					},
+					&cli.StringFlag{
+						Name:  "templates",
+						Usage: "Override page templates with the *.tmpl files in this dir",
+					},
+					&cli.StringFlag{
+						Name:  "group-by",
+						Usage: "Group concepts into containers in detail diagrams by tag, group or component",
+					},
+					&cli.BoolFlag{
+						Name:  "group-by-tag",
+						Usage: "Deprecated: use --group-by tag",
+					},
+					&cli.IntFlag{
+						Name:  "radius",
+						Value: 1,
+						Usage: "Number of hops from a concept to include in its diagram",
+					},
+					&cli.IntFlag{
+						Name:  "max-nodes",
+						Usage: "Maximum number of concepts in a concept's diagram, 0 for no limit",
+					},
+					&cli.BoolFlag{
+						Name:  "collapse-groups",
+						Usage: "Draw summary diagrams as an overview with each group collapsed into a single node",
+					},
+					&cli.IntFlag{
+						Name:  "max-detail-nodes",
+						Usage: "Split detail diagrams with more concepts than this into linked parts, 0 for no limit",
+					},
+					&cli.StringFlag{
+						Name:  "split-by",
+						Value: "key-concept",
+						Usage: "Split oversized detail diagrams by key-concept or community",
+					},
 				},
 				Action: func(c *cli.Context) error {
 					outputDir := c.String("outdir")
@@ -40,16 +88,486 @@ func main() {
 						return fmt.Errorf("input file is required")
 					}
 
-					maps, err := conceptmap.LoadFromYamlFile(inputFile)
+					maps, err := conceptmap.LoadFromFile(inputFile)
 					if err != nil {
 						return err
 					}
 
-					siteGenererator := sitegenerator.NewMarkdownSiteGenerator(outputDir)
+					var flavour sitegenerator.SiteFlavour
+
+					switch c.String("flavour") {
+					case "mkdocs":
+						flavour = sitegenerator.MkDocs
+					case "hugo":
+						flavour = sitegenerator.Hugo
+					case "docusaurus":
+						flavour = sitegenerator.Docusaurus
+					default:
+						return fmt.Errorf("unknown site flavour '%s'", c.String("flavour"))
+					}
+
+					templates := sitegenerator.DefaultTemplates()
+
+					if dir := c.String("templates"); dir != "" {
+						templates, err = sitegenerator.LoadTemplates(dir)
+						if err != nil {
+							return err
+						}
+					}
+
+					diagramOpts := []diagrams.D2DiagramGeneratorOption{
+						diagrams.WithNeighbourhoodRadius(c.Int("radius")),
+						diagrams.WithMaxNodes(c.Int("max-nodes")),
+					}
+
+					groupBy := c.String("group-by")
+
+					if c.Bool("group-by-tag") {
+						if groupBy != "" && groupBy != "tag" {
+							return fmt.Errorf("--group-by-tag can't be used with --group-by %s", groupBy)
+						}
+						groupBy = "tag"
+					}
+
+					if groupBy != "" {
+						var grouper diagrams.ConceptGrouper
+
+						switch groupBy {
+						case "tag":
+							grouper = diagrams.GroupByTags()
+						case "group":
+							grouper = diagrams.GroupByField()
+						case "component":
+							grouper = diagrams.GroupByConnectedComponent()
+						default:
+							return fmt.Errorf("unknown grouping '%s'", groupBy)
+						}
+
+						diagramOpts = append(diagramOpts, diagrams.WithDetailGrouper(grouper))
+
+						if c.Bool("collapse-groups") {
+							diagramOpts = append(diagramOpts, diagrams.WithSummaryGrouper(grouper))
+						}
+					}
+
+					var partitioner diagrams.ConceptGrouper
+
+					switch c.String("split-by") {
+					case "key-concept":
+						partitioner = diagrams.GroupByKeyConcept()
+					case "community":
+						partitioner = diagrams.GroupByCommunity()
+					default:
+						return fmt.Errorf("unknown split '%s'", c.String("split-by"))
+					}
+
+					siteGenererator := sitegenerator.NewMarkdownSiteGenerator(
+						outputDir,
+						sitegenerator.WithSiteFlavour(flavour),
+						sitegenerator.WithTemplates(templates),
+						sitegenerator.WithMaxDetailNodes(c.Int("max-detail-nodes"), partitioner),
+						sitegenerator.WithDiagramGenerator(diagrams.NewD2DiagramGenerator(diagramOpts...)))
 
 					return siteGenererator.GenerateSite(ctx, maps)
 				},
 			},
+			{
+				Name:      "path",
+				Usage:     "Show how two concepts are connected",
+				ArgsUsage: "<file> <from> <to>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Show every chain of propositions connecting the concepts, rather than just the shortest",
+					},
+					&cli.IntFlag{
+						Name:  "max-length",
+						Value: 4,
+						Usage: "Maximum number of propositions in each chain when showing all chains, 0 for no limit",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Draw the chains in the first map that connects the concepts to this svg file",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					inputFile := c.Args().Get(0)
+					fromLabel := c.Args().Get(1)
+					toLabel := c.Args().Get(2)
+
+					if inputFile == "" || fromLabel == "" || toLabel == "" {
+						return fmt.Errorf("input file, from and to concepts are required")
+					}
+
+					maps, err := conceptmap.LoadFromFile(inputFile)
+					if err != nil {
+						return err
+					}
+
+					found := false
+
+					for _, cmap := range maps {
+						from, to := cmap.Concept(fromLabel), cmap.Concept(toLabel)
+						if from == nil || to == nil {
+							continue
+						}
+
+						var paths []conceptmap.PropositionList
+
+						if c.Bool("all") {
+							paths = cmap.Propositions.Paths(from, to, c.Int("max-length"))
+						} else if path := cmap.Propositions.ShortestPath(from, to); len(path) > 0 {
+							paths = []conceptmap.PropositionList{path}
+						}
+
+						if len(paths) == 0 {
+							continue
+						}
+
+						if len(maps) > 1 {
+							fmt.Printf("# %s\n\n", cmap.Title)
+						}
+
+						for _, path := range paths {
+							for _, p := range path {
+								fmt.Println(p.String())
+							}
+							fmt.Println()
+						}
+
+						if file := c.String("output"); file != "" && !found {
+							if err := diagrams.NewD2DiagramGenerator().GeneratePathSVG(ctx, cmap, from, to, paths, file); err != nil {
+								return err
+							}
+						}
+
+						found = true
+					}
+
+					if !found {
+						return fmt.Errorf("no path between '%s' and '%s'", fromLabel, toLabel)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "query",
+				Usage:     "Find propositions matching a query, such as left:pod predicate~runs right:tag:infra",
+				ArgsUsage: "<file> <query>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "text",
+						Usage: "Print matching propositions as text or json, or draw them as an svg",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Write svg diagrams to this file",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					inputFile := c.Args().Get(0)
+					query := c.Args().Get(1)
+
+					if inputFile == "" {
+						return fmt.Errorf("input file is required")
+					}
+
+					filter, err := conceptmap.ParseQuery(query)
+					if err != nil {
+						return err
+					}
+
+					maps, err := conceptmap.LoadFromFile(inputFile)
+					if err != nil {
+						return err
+					}
+
+					type match struct {
+						Map       string `json:"map"`
+						Left      string `json:"left"`
+						Predicate string `json:"predicate"`
+						Right     string `json:"right"`
+					}
+
+					matches := []*match{}
+					propositions := conceptmap.PropositionList{}
+
+					for _, cmap := range maps {
+						for _, p := range cmap.Propositions.Where(filter) {
+							propositions = append(propositions, p)
+							matches = append(matches, &match{
+								Map:       cmap.Title,
+								Left:      p.Left.Label,
+								Predicate: string(p.Predicate),
+								Right:     p.Right.Label,
+							})
+						}
+					}
+
+					switch c.String("format") {
+					case "text":
+						for _, p := range propositions {
+							fmt.Println(p.String())
+						}
+					case "json":
+						enc := json.NewEncoder(os.Stdout)
+						enc.SetIndent("", "  ")
+						return enc.Encode(matches)
+					case "svg":
+						file := c.String("output")
+						if file == "" {
+							return fmt.Errorf("output is required for svg")
+						}
+
+						return diagrams.NewD2DiagramGenerator().GeneratePropositionsSVG(ctx, propositions, file)
+					default:
+						return fmt.Errorf("unknown format '%s'", c.String("format"))
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "convert",
+				Usage:     "Convert a concept map file, such as a CmapTools cxl file, to yaml",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Write yaml to this file rather than stdout",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					inputFile := c.Args().Get(0)
+
+					if inputFile == "" {
+						return fmt.Errorf("input file is required")
+					}
+
+					maps, err := conceptmap.LoadFromFile(inputFile)
+					if err != nil {
+						return err
+					}
+
+					w := os.Stdout
+
+					if file := c.String("output"); file != "" {
+						f, err := os.Create(file)
+						if err != nil {
+							return err
+						}
+
+						defer f.Close()
+
+						w = f
+					}
+
+					return conceptmap.WriteYaml(w, maps)
+				},
+			},
+			{
+				Name:      "fmt",
+				Usage:     "Rewrite concept map yaml files in a canonical form",
+				ArgsUsage: "<file>...",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "check",
+						Usage: "List files that aren't formatted, and fail if there are any, rather than rewriting them",
+					},
+					&cli.IntFlag{
+						Name:  "width",
+						Value: conceptmap.DefaultWrapWidth,
+						Usage: "Wrap descriptions to this width",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return fmt.Errorf("at least one input file is required")
+					}
+
+					formatter := conceptmap.NewYamlFormatter(conceptmap.WithWrapWidth(c.Int("width")))
+					unformatted := 0
+
+					for _, file := range c.Args().Slice() {
+						src, err := os.ReadFile(file)
+						if err != nil {
+							return err
+						}
+
+						if f := conceptmap.DetectFormat(file, src); f != conceptmap.FormatYaml {
+							return fmt.Errorf("%s: only yaml concept maps can be formatted, not %s", file, f)
+						}
+
+						formatted, err := formatter.Format(src)
+						if err != nil {
+							return fmt.Errorf("%s: %w", file, err)
+						}
+
+						if bytes.Equal(src, formatted) {
+							continue
+						}
+
+						if c.Bool("check") {
+							fmt.Println(file)
+							unformatted++
+							continue
+						}
+
+						info, err := os.Stat(file)
+						if err != nil {
+							return err
+						}
+
+						if err := os.WriteFile(file, formatted, info.Mode().Perm()); err != nil {
+							return err
+						}
+					}
+
+					if unformatted > 0 {
+						return fmt.Errorf("%d file(s) aren't formatted", unformatted)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "export",
+				Usage:     "Export concept maps to another format",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "yaml",
+						Usage: "Export to yaml, csv, tsv, cxl, turtle, jsonld, skos, cypher, graphml or gexf",
+					},
+					&cli.StringFlag{
+						Name:  "base-iri",
+						Value: rdf.DefaultBaseIRI,
+						Usage: "Name rdf resources relative to this iri when exporting to turtle, jsonld or skos",
+					},
+					&cli.BoolFlag{
+						Name:  "cypher-predicate-property",
+						Usage: "Export predicates as a property of RELATES_TO relationships, rather than as relationship types, when exporting to cypher",
+					},
+					&cli.StringSliceFlag{
+						Name:  "skos-relation",
+						Usage: "Export a predicate as a skos broader, narrower or related relation, such as 'is a=broader'. Replaces the default mapping",
+					},
+					&cli.StringFlag{
+						Name:  "map",
+						Usage: "Only export the concept map with this title",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Write to this file rather than stdout",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					inputFile := c.Args().Get(0)
+
+					if inputFile == "" {
+						return fmt.Errorf("input file is required")
+					}
+
+					var skosMapping map[string]rdf.SKOSRelation
+
+					for _, r := range c.StringSlice("skos-relation") {
+						predicate, name, ok := strings.Cut(r, "=")
+						relation, valid := rdf.ParseSKOSRelation(name)
+
+						if !ok || !valid {
+							return fmt.Errorf("invalid skos relation '%s', expected predicate=broader|narrower|related", r)
+						}
+
+						if skosMapping == nil {
+							skosMapping = map[string]rdf.SKOSRelation{}
+						}
+
+						skosMapping[predicate] = relation
+					}
+
+					cypherOpts := []cypher.ExporterOption{}
+
+					if c.Bool("cypher-predicate-property") {
+						cypherOpts = append(cypherOpts, cypher.WithPredicatesAsProperties())
+					}
+
+					exporters := map[string]conceptmap.Exporter{
+						"yaml":    conceptmap.YamlExporter,
+						"csv":     conceptmap.CsvExporter,
+						"tsv":     conceptmap.TsvExporter,
+						"cxl":     cxl.Exporter,
+						"turtle":  rdf.NewTurtleExporter(rdf.WithBaseIRI(c.String("base-iri"))),
+						"jsonld":  rdf.NewJSONLDExporter(rdf.WithBaseIRI(c.String("base-iri"))),
+						"skos":    rdf.NewTurtleExporter(rdf.WithBaseIRI(c.String("base-iri")), rdf.WithSKOS(skosMapping)),
+						"cypher":  cypher.NewExporter(cypherOpts...),
+						"graphml": network.GraphMLExporter,
+						"gexf":    network.GEXFExporter,
+					}
+
+					exporter, ok := exporters[c.String("format")]
+					if !ok {
+						return fmt.Errorf("unknown export format '%s'", c.String("format"))
+					}
+
+					maps, err := conceptmap.LoadFromFile(inputFile)
+					if err != nil {
+						return err
+					}
+
+					if title := c.String("map"); title != "" {
+						selected := []*conceptmap.ConceptMap{}
+
+						for _, m := range maps {
+							if strings.EqualFold(m.Title, title) {
+								selected = append(selected, m)
+							}
+						}
+
+						if len(selected) == 0 {
+							return fmt.Errorf("no concept map titled '%s'", title)
+						}
+
+						maps = selected
+					}
+
+					w := os.Stdout
+
+					if file := c.String("output"); file != "" {
+						f, err := os.Create(file)
+						if err != nil {
+							return err
+						}
+
+						defer f.Close()
+
+						w = f
+					}
+
+					return exporter.Export(w, maps)
+				},
+			},
+			{
+				Name:  "lsp",
+				Usage: "Run a language server for concept map yaml files over stdin and stdout",
+				Action: func(c *cli.Context) error {
+					return lsp.NewServer(os.Stdin, os.Stdout).Serve(ctx)
+				},
+			},
+			{
+				Name:  "schema",
+				Usage: "Print the json schema for concept map files",
+				Action: func(c *cli.Context) error {
+					_, err := os.Stdout.Write(conceptmap.JSONSchema)
+					return err
+				},
+			},
 		},
 	}
 
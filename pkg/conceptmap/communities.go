@@ -0,0 +1,178 @@
+package conceptmap
+
+// Communities partitions the concepts in the propositions into densely connected
+// communities using the Louvain method, which greedily maximises modularity,
+// ignoring the direction of each proposition. Concepts are visited in the order
+// they first appear, and only moved into a community that strictly improves
+// modularity, so the result is deterministic
+func (ps PropositionList) Communities() [][]*Concept {
+	concepts := ps.Concepts()
+	index := map[string]int{}
+
+	for i, c := range concepts {
+		index[c.Key()] = i
+	}
+
+	// adjacency holds the weight of the edges between nodes. Self loops are
+	// counted twice, so that the weights of a node's edges sum to its degree
+	adjacency := make([]map[int]int, len(concepts))
+
+	for i := range adjacency {
+		adjacency[i] = map[int]int{}
+	}
+
+	for _, p := range ps {
+		l, r := index[p.Left.Key()], index[p.Right.Key()]
+		adjacency[l][r]++
+		adjacency[r][l]++
+	}
+
+	// community maps each concept to its community in the current level
+	community := make([]int, len(concepts))
+
+	for i := range community {
+		community[i] = i
+	}
+
+	for {
+		moved, assignment := louvainMoveNodes(adjacency)
+		if !moved {
+			break
+		}
+
+		for i := range community {
+			community[i] = assignment[community[i]]
+		}
+
+		adjacency = louvainAggregate(adjacency, assignment)
+	}
+
+	output := [][]*Concept{}
+	position := map[int]int{}
+
+	for i, c := range concepts {
+		j, ok := position[community[i]]
+		if !ok {
+			j = len(output)
+			position[community[i]] = j
+			output = append(output, []*Concept{})
+		}
+
+		output[j] = append(output[j], c)
+	}
+
+	return output
+}
+
+// louvainMoveNodes repeatedly moves each node into the neighbouring community
+// that most increases modularity, until no move does. It returns whether any
+// node moved, and the community of each node, numbered from 0 in order of first
+// appearance
+func louvainMoveNodes(adjacency []map[int]int) (bool, []int) {
+	n := len(adjacency)
+	community := make([]int, n)
+	degree := make([]int, n)
+	total := make([]int, n)
+	twiceWeight := 0
+
+	for i, edges := range adjacency {
+		community[i] = i
+
+		for _, w := range edges {
+			degree[i] += w
+		}
+
+		total[i] = degree[i]
+		twiceWeight += degree[i]
+	}
+
+	if twiceWeight == 0 {
+		return false, community
+	}
+
+	moved := false
+
+	for changed := true; changed; {
+		changed = false
+
+		for i := 0; i < n; i++ {
+			current := community[i]
+			total[current] -= degree[i]
+
+			links := map[int]int{}
+			candidates := []int{current}
+
+			for j, w := range adjacency[i] {
+				if j == i {
+					continue
+				}
+
+				if _, ok := links[community[j]]; !ok && community[j] != current {
+					candidates = append(candidates, community[j])
+				}
+
+				links[community[j]] += w
+			}
+
+			// Gains are compared scaled by twice the total weight, so that they
+			// are exact integers
+			gain := func(c int) int {
+				return links[c]*twiceWeight - total[c]*degree[i]
+			}
+
+			best := current
+
+			for _, c := range candidates {
+				if g, b := gain(c), gain(best); g > b || (g == b && best != current && c < best) {
+					best = c
+				}
+			}
+
+			community[i] = best
+			total[best] += degree[i]
+
+			if best != current {
+				changed = true
+				moved = true
+			}
+		}
+	}
+
+	numbers := map[int]int{}
+
+	for i, c := range community {
+		if _, ok := numbers[c]; !ok {
+			numbers[c] = len(numbers)
+		}
+
+		community[i] = numbers[c]
+	}
+
+	return moved, community
+}
+
+// louvainAggregate builds the graph whose nodes are the communities in
+// assignment, with edges weighted by the sum of the edges between them
+func louvainAggregate(adjacency []map[int]int, assignment []int) []map[int]int {
+	n := 0
+
+	for _, c := range assignment {
+		if c+1 > n {
+			n = c + 1
+		}
+	}
+
+	aggregated := make([]map[int]int, n)
+
+	for i := range aggregated {
+		aggregated[i] = map[int]int{}
+	}
+
+	for i, edges := range adjacency {
+		for j, w := range edges {
+			aggregated[assignment[i]][assignment[j]] += w
+		}
+	}
+
+	return aggregated
+}
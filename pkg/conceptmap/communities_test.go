@@ -0,0 +1,84 @@
+package conceptmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+// linking returns propositions linking each pair of labels, in order
+func linking(labels ...string) PropositionList {
+	concepts := map[string]*Concept{}
+	ps := PropositionList{}
+
+	concept := func(l string) *Concept {
+		if _, ok := concepts[l]; !ok {
+			concepts[l] = &Concept{Label: l}
+		}
+		return concepts[l]
+	}
+
+	for i := 0; i+1 < len(labels); i += 2 {
+		ps = append(ps, &Proposition{Left: concept(labels[i]), Predicate: "links to", Right: concept(labels[i+1])})
+	}
+
+	return ps
+}
+
+func communityLabels(communities [][]*Concept) []string {
+	output := []string{}
+
+	for _, community := range communities {
+		s := ""
+		for _, c := range community {
+			s += c.Label
+		}
+		output = append(output, s)
+	}
+
+	return output
+}
+
+func TestCommunitiesSplitsAChain(t *testing.T) {
+	ps := linking("A", "B", "B", "C", "C", "D", "D", "E", "E", "F", "F", "G", "G", "H")
+
+	communities := ps.Communities()
+
+	if len(communities) < 2 {
+		t.Fatalf("expected a chain of 8 concepts to be split, got %v", communityLabels(communities))
+	}
+
+	seen := 0
+
+	for _, community := range communities {
+		seen += len(community)
+	}
+
+	if seen != 8 {
+		t.Errorf("expected every concept in exactly one community, got %v", communityLabels(communities))
+	}
+}
+
+func TestCommunitiesSplitsCliquesJoinedByABridge(t *testing.T) {
+	ps := linking(
+		"A", "B", "B", "C", "C", "A",
+		"D", "E", "E", "F", "F", "D",
+		"C", "D")
+
+	got := fmt.Sprint(communityLabels(ps.Communities()))
+
+	if got != "[ABC DEF]" {
+		t.Errorf("expected communities [ABC DEF], got %s", got)
+	}
+}
+
+func TestCommunitiesIsDeterministic(t *testing.T) {
+	ps := linking("A", "B", "B", "C", "C", "D", "D", "E", "E", "F", "F", "G", "G", "H", "H", "A", "A", "E")
+
+	want := fmt.Sprint(communityLabels(ps.Communities()))
+
+	for i := 0; i < 20; i++ {
+		if got := fmt.Sprint(communityLabels(ps.Communities())); got != want {
+			t.Fatalf("expected %s on every run, got %s", want, got)
+		}
+	}
+}
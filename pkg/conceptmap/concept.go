@@ -4,12 +4,56 @@ import "github.com/gosimple/slug"
 
 // Concept is a node in the concept map
 type Concept struct {
-	Label        string `yaml:"label"`
-	Description  string `yaml:"description"`
-	IsKeyConcept bool   `yaml:"isKeyConcept"`
+	Label        string   `yaml:"label" json:"label" toml:"label"`
+	Aliases      []string `yaml:"aliases" json:"aliases" toml:"aliases"`
+	Description  string   `yaml:"description" json:"description" toml:"description"`
+	IsKeyConcept bool     `yaml:"isKeyConcept" json:"isKeyConcept" toml:"isKeyConcept"`
+	Tags         []string `yaml:"tags" json:"tags" toml:"tags"`
+	Categories   []string `yaml:"categories" json:"categories" toml:"categories"`
+	Group        string   `yaml:"group" json:"group" toml:"group"`
 }
 
 // Key is normalised key of the concept
 func (c *Concept) Key() string {
 	return slug.Make(c.Label)
 }
+
+// HasTag returns true if the concept is tagged with tag. Tags are compared by
+// their slugs
+func (c *Concept) HasTag(tag string) bool {
+	return containsSlug(c.Tags, tag)
+}
+
+// HasCategory returns true if the concept belongs to category. Categories are
+// compared by their slugs
+func (c *Concept) HasCategory(category string) bool {
+	return containsSlug(c.Categories, category)
+}
+
+func containsSlug(values []string, s string) bool {
+	key := slug.Make(s)
+
+	for _, v := range values {
+		if slug.Make(v) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasKey returns true if key matches the key of the concept's label or any of its
+// aliases
+func (c *Concept) HasKey(key string) bool {
+	if c.Key() == key {
+		return true
+	}
+
+	for _, a := range c.Aliases {
+		if slug.Make(a) == key {
+			return true
+		}
+	}
+
+	return false
+}
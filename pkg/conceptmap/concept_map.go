@@ -39,6 +39,35 @@ func (m *ConceptMap) KeyConcepts() []*Concept {
 	return output
 }
 
+// ConceptsWithTag returns all concepts in the map tagged with tag
+func (m *ConceptMap) ConceptsWithTag(tag string) []*Concept {
+	output := []*Concept{}
+
+	for _, c := range m.Concepts {
+		if c.HasTag(tag) {
+			output = append(output, c)
+		}
+	}
+
+	return output
+}
+
+// Tags returns the distinct tags of all concepts in the map, in the order they
+// first appear
+func (m *ConceptMap) Tags() []string {
+	output := []string{}
+
+	for _, c := range m.Concepts {
+		for _, t := range c.Tags {
+			if !containsSlug(output, t) {
+				output = append(output, t)
+			}
+		}
+	}
+
+	return output
+}
+
 // ConceptsRelatedTo returns all concepts that are related to c via a Proposition
 func (m *ConceptMap) ConceptsRelatedTo(concepts ...*Concept) []*Concept {
 	output := []*Concept{}
@@ -62,3 +91,23 @@ func (m *ConceptMap) ConceptsRelatedTo(concepts ...*Concept) []*Concept {
 
 	return output
 }
+
+// Concept returns the concept whose label or alias has the same key as label, or
+// nil if there is no such concept in the map
+func (m *ConceptMap) Concept(label string) *Concept {
+	key := slug.Make(label)
+
+	for _, c := range m.Concepts {
+		if c.Key() == key {
+			return c
+		}
+	}
+
+	for _, c := range m.Concepts {
+		if c.HasKey(key) {
+			return c
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package conceptmap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Headings of the columns of the propositions and concepts tables in csv and tsv
+// files
+var (
+	propositionColumns = []string{"left", "predicate", "right"}
+	conceptColumns     = []string{"label", "description", "isKeyConcept"}
+)
+
+// LoadFromCsvReader loads a Map from an io.Reader in csv format. Each row holds
+// the left concept, predicate and right concept of a proposition, which are
+// used as they are written rather than parsed as a sentence. The rows can be
+// followed by a concepts table, which starts with a heading row of label,
+// description and isKeyConcept, and holds the details of the concepts. Either
+// table can start with a heading row naming its columns, in which case they can
+// be in any order. Csv files can't hold a title, so LoadFromFile titles the map
+// with the name of the file
+func LoadFromCsvReader(r io.Reader) ([]*ConceptMap, error) {
+	return loadFromDelimitedReader(r, ',')
+}
+
+// LoadFromTsvReader loads a Map from an io.Reader in tsv format. The format is
+// the same as csv, but with columns separated by tabs
+func LoadFromTsvReader(r io.Reader) ([]*ConceptMap, error) {
+	return loadFromDelimitedReader(r, '\t')
+}
+
+func loadFromDelimitedReader(r io.Reader, comma rune) ([]*ConceptMap, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	m := &ConceptMap{
+		Concepts:     []*Concept{},
+		Propositions: []*Proposition{},
+	}
+
+	columns := indexColumns(propositionColumns, nil)
+	inConcepts := false
+	first := true
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+
+		if isBlankRow(row) {
+			continue
+		}
+
+		if first && isHeadingRow(row, propositionColumns) {
+			columns = indexColumns(propositionColumns, row)
+			first = false
+			continue
+		}
+
+		first = false
+
+		if isHeadingRow(row, conceptColumns) {
+			if _, ok := headingIndex(row, "label"); !ok {
+				return nil, fmt.Errorf("line %d: concepts table has no label column", line)
+			}
+
+			columns = indexColumns(conceptColumns, row)
+			inConcepts = true
+			continue
+		}
+
+		if inConcepts {
+			if err := applyConceptRow(m, row, columns); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+
+		left, predicate, right := label(row, columns["left"]), label(row, columns["predicate"]), label(row, columns["right"])
+
+		switch {
+		case left == "":
+			return nil, fmt.Errorf("line %d: could not find left concept", line)
+		case predicate == "":
+			return nil, fmt.Errorf("line %d: could not find predicate", line)
+		case right == "":
+			return nil, fmt.Errorf("line %d: could not find right concept", line)
+		}
+
+		appendProposition(left, predicate, right, &m.Propositions, &m.Concepts)
+	}
+
+	if err := m.ValidateReferences(); err != nil {
+		return nil, err
+	}
+
+	return []*ConceptMap{m}, nil
+}
+
+// applyConceptRow sets the details of the concept in a row of the concepts table.
+// Only the details whose columns are in the table are set. As with the other
+// formats, details of concepts that aren't in any proposition are ignored
+func applyConceptRow(m *ConceptMap, row []string, columns map[string]int) error {
+	l := label(row, columns["label"])
+	if l == "" {
+		return fmt.Errorf("could not find concept label")
+	}
+
+	isKeyConcept := false
+
+	if v := cell(row, columns["iskeyconcept"]); v != "" {
+		b, err := parseCsvBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid isKeyConcept '%s' for concept '%s'", v, l)
+		}
+		isKeyConcept = b
+	}
+
+	for _, c := range m.Concepts {
+		if c.Label != l {
+			continue
+		}
+
+		if columns["description"] >= 0 {
+			c.Description = cell(row, columns["description"])
+		}
+
+		if columns["iskeyconcept"] >= 0 {
+			c.IsKeyConcept = isKeyConcept
+		}
+	}
+
+	return nil
+}
+
+// indexColumns maps the lower cased names of columns to their index in heading.
+// Columns missing from heading, or all columns if there is no heading, are
+// assumed to be in the order of names
+func indexColumns(names []string, heading []string) map[string]int {
+	columns := map[string]int{}
+
+	for i, name := range names {
+		if idx, ok := headingIndex(heading, name); ok {
+			columns[strings.ToLower(name)] = idx
+		} else if heading == nil {
+			columns[strings.ToLower(name)] = i
+		} else {
+			columns[strings.ToLower(name)] = -1
+		}
+	}
+
+	return columns
+}
+
+func headingIndex(heading []string, name string) (int, bool) {
+	for i, h := range heading {
+		if strings.EqualFold(strings.ReplaceAll(h, " ", ""), name) {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// isHeadingRow returns true if every non blank cell of row names one of names
+func isHeadingRow(row []string, names []string) bool {
+	found := false
+
+	for _, c := range row {
+		if c == "" {
+			continue
+		}
+
+		if _, ok := headingIndex(names, strings.ReplaceAll(c, " ", "")); !ok {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
+
+func isBlankRow(row []string) bool {
+	for _, c := range row {
+		if c != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+
+	return row[i]
+}
+
+// label returns the cell at i with its whitespace normalised, as concept labels
+// and predicates are written on a single line
+func label(row []string, i int) string {
+	return strings.Join(strings.Fields(cell(row, i)), " ")
+}
+
+// parseCsvBool parses the ways that spreadsheets and people write booleans
+func parseCsvBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "y", "x":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+
+	return strconv.ParseBool(s)
+}
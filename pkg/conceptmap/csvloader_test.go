@@ -0,0 +1,212 @@
+package conceptmap
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func propositionStrings(ps PropositionList) string {
+	output := []string{}
+
+	for _, p := range ps {
+		output = append(output, strings.Join([]string{p.Left.Label, string(p.Predicate), p.Right.Label}, " | "))
+	}
+
+	return strings.Join(output, "\n")
+}
+
+func TestLoadFromCsvReaderHeadings(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "no heading",
+			src:  "Kubernetes,runs,Pods\nPods,contain,Containers\n",
+			want: "Kubernetes | runs | Pods\nPods | contain | Containers",
+		},
+		{
+			name: "heading",
+			src:  "left,predicate,right\nKubernetes,runs,Pods\n",
+			want: "Kubernetes | runs | Pods",
+		},
+		{
+			name: "heading in any case",
+			src:  "Left, Predicate , RIGHT\nKubernetes,runs,Pods\n",
+			want: "Kubernetes | runs | Pods",
+		},
+		{
+			name: "reordered columns",
+			src:  "right,left,predicate\nPods,Kubernetes,runs\n",
+			want: "Kubernetes | runs | Pods",
+		},
+		{
+			name: "concept names like headings",
+			src:  "Left,is a,Right\n",
+			want: "Left | is a | Right",
+		},
+		{
+			name: "blank rows and whitespace",
+			src:  "\nleft,predicate,right\n,,\n  Container   Images , are built from,Dockerfiles\n",
+			want: "Container Images | are built from | Dockerfiles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maps, err := LoadFromCsvReader(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := propositionStrings(maps[0].Propositions); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFromCsvReaderConcepts(t *testing.T) {
+	src := strings.Join([]string{
+		"Kubernetes,runs,Pods",
+		"",
+		"isKeyConcept,label,description",
+		"yes,Pods,The smallest unit",
+		"",
+		"label,isKeyConcept",
+		"Kubernetes,true",
+	}, "\n")
+
+	maps, err := LoadFromCsvReader(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pods := maps[0].Concept("Pods")
+	if pods.Description != "The smallest unit" || !pods.IsKeyConcept {
+		t.Errorf("expected Pods to be a described key concept, got %+v", pods)
+	}
+
+	kubernetes := maps[0].Concept("Kubernetes")
+	if !kubernetes.IsKeyConcept {
+		t.Errorf("expected Kubernetes to be a key concept, got %+v", kubernetes)
+	}
+
+	// A table without a description column leaves descriptions as they are
+	src += "\nlabel,isKeyConcept\nPods,no\n"
+
+	maps, err = LoadFromCsvReader(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pods := maps[0].Concept("Pods"); pods.Description != "The smallest unit" || pods.IsKeyConcept {
+		t.Errorf("expected Pods to keep its description and not be a key concept, got %+v", pods)
+	}
+}
+
+func TestLoadFromCsvReaderErrors(t *testing.T) {
+	for _, src := range []string{
+		"Kubernetes,runs\n",
+		",runs,Pods\n",
+		"Kubernetes,runs,Pods\nlabel,description\n,Missing label\n",
+		"Kubernetes,runs,Pods\ndescription,isKeyConcept\nA thing,true\n",
+		"Kubernetes,runs,Pods\nlabel,isKeyConcept\nPods,maybe\n",
+	} {
+		if _, err := LoadFromCsvReader(strings.NewReader(src)); err == nil {
+			t.Errorf("expected an error loading\n%s", src)
+		}
+	}
+}
+
+func TestCsvRoundTrips(t *testing.T) {
+	maps, err := LoadFromYamlReader(strings.NewReader(`title: Kubernetes
+propositions: |
+  Kubernetes runs Pods
+  Pods contain Containers
+  iPhone | is a | "smart, phone"
+concepts:
+  Pods:
+    description: |
+      The smallest unit, with "quotes"
+
+      and two paragraphs
+    isKeyConcept: true
+  Kubernetes:
+    isKeyConcept: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, write := range map[string]func(*bytes.Buffer, []*ConceptMap) error{
+		"csv": func(b *bytes.Buffer, maps []*ConceptMap) error { return WriteCsv(b, maps) },
+		"tsv": func(b *bytes.Buffer, maps []*ConceptMap) error { return WriteTsv(b, maps) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			if err := write(&b, maps); err != nil {
+				t.Fatal(err)
+			}
+
+			file := filepath.Join(t.TempDir(), "Kubernetes."+name)
+			if err := os.WriteFile(file, b.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := LoadFromFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded[0].Title != "Kubernetes" {
+				t.Errorf("expected the map to be titled after the file, got %q", loaded[0].Title)
+			}
+
+			if got, want := propositionStrings(loaded[0].Propositions), propositionStrings(maps[0].Propositions); got != want {
+				t.Errorf("got propositions\n%s\nwant\n%s", got, want)
+			}
+
+			for _, c := range maps[0].Concepts {
+				l := loaded[0].Concept(c.Label)
+
+				if l.Description != strings.TrimSpace(c.Description) || l.IsKeyConcept != c.IsKeyConcept {
+					t.Errorf("got concept %+v, want %+v", l, c)
+				}
+			}
+
+			var again bytes.Buffer
+
+			if err := write(&again, loaded); err != nil {
+				t.Fatal(err)
+			}
+
+			if again.String() != b.String() {
+				t.Errorf("expected writing the loaded map to give the same %s, got\n%s\nwant\n%s", name, again.String(), b.String())
+			}
+		})
+	}
+}
+
+func TestLoadFromFileNamesUnknownReferences(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "Kubernetes.csv")
+	src := "Kubernetes,runs,Pods\n\nlabel,description\nPods,Run on [[Nodes]]\n"
+
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadFromFile(file)
+	if err == nil {
+		t.Fatal("expected an error for the unknown reference to Nodes")
+	}
+
+	if msg := err.Error(); !strings.Contains(msg, file) || strings.Contains(msg, "''") {
+		t.Errorf("expected the error to name the file rather than an empty title, got %q", msg)
+	}
+}
@@ -0,0 +1,80 @@
+package conceptmap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteCsv writes a single map to w in the csv format read by LoadFromCsvReader.
+// Titles and the map's description have nowhere to go in a csv file, so are
+// lost, as are concept details other than descriptions and key concepts
+func WriteCsv(w io.Writer, maps []*ConceptMap) error {
+	return writeDelimited(w, maps, ',')
+}
+
+// WriteTsv writes a single map to w in the tsv format read by LoadFromTsvReader
+func WriteTsv(w io.Writer, maps []*ConceptMap) error {
+	return writeDelimited(w, maps, '\t')
+}
+
+func writeDelimited(w io.Writer, maps []*ConceptMap, comma rune) error {
+	if len(maps) != 1 {
+		return fmt.Errorf("a csv or tsv file holds a single concept map, but there are %d", len(maps))
+	}
+
+	m := maps[0]
+
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(propositionColumns); err != nil {
+		return err
+	}
+
+	for _, p := range m.Propositions {
+		if err := cw.Write([]string{p.Left.Label, string(p.Predicate), p.Right.Label}); err != nil {
+			return err
+		}
+	}
+
+	rows := [][]string{}
+
+	for _, c := range m.Concepts {
+		description := strings.TrimSpace(c.Description)
+
+		if description == "" && !c.IsKeyConcept {
+			continue
+		}
+
+		rows = append(rows, []string{c.Label, description, strconv.FormatBool(c.IsKeyConcept)})
+	}
+
+	if len(rows) > 0 {
+		// A blank line separates the tables, so they read as two tables when opened
+		// in a spreadsheet
+		cw.Flush()
+
+		if err := cw.Error(); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+
+		if err := cw.Write(conceptColumns); err != nil {
+			return err
+		}
+
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
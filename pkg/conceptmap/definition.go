@@ -0,0 +1,48 @@
+package conceptmap
+
+// definition is the format that concept maps are written in, whichever of the
+// supported file formats is used
+type definition struct {
+	Title        string              `yaml:"title" json:"title" toml:"title"`
+	Description  string              `yaml:"description" json:"description" toml:"description"`
+	Propositions string              `yaml:"propositions" json:"propositions" toml:"propositions"`
+	Concepts     map[string]*Concept `yaml:"concepts" json:"concepts" toml:"concepts"`
+}
+
+// conceptMap builds a concept map from the definition, parsing its propositions
+// with parser and validating its concept references
+func (def *definition) conceptMap(parser *PropositionParser) (*ConceptMap, error) {
+	m := &ConceptMap{
+		Title:        def.Title,
+		Description:  def.Description,
+		Concepts:     []*Concept{},
+		Propositions: []*Proposition{},
+	}
+
+	if err := parser.Parse(def.Propositions, &m.Propositions, &m.Concepts); err != nil {
+		return nil, err
+	}
+
+	for k, v := range def.Concepts {
+		if v == nil {
+			continue
+		}
+
+		for _, c := range m.Concepts {
+			if c.Label == k {
+				c.Aliases = v.Aliases
+				c.Description = v.Description
+				c.IsKeyConcept = v.IsKeyConcept
+				c.Tags = v.Tags
+				c.Categories = v.Categories
+				c.Group = v.Group
+			}
+		}
+	}
+
+	if err := m.ValidateReferences(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
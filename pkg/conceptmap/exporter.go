@@ -0,0 +1,24 @@
+package conceptmap
+
+import "io"
+
+// Exporter writes concept maps to a writer in some format
+type Exporter interface {
+	Export(w io.Writer, maps []*ConceptMap) error
+}
+
+// ExporterFunc adapts a function to the Exporter interface
+type ExporterFunc func(io.Writer, []*ConceptMap) error
+
+func (fn ExporterFunc) Export(w io.Writer, maps []*ConceptMap) error {
+	return fn(w, maps)
+}
+
+// YamlExporter writes concept maps in the yaml format
+var YamlExporter Exporter = ExporterFunc(WriteYaml)
+
+// CsvExporter writes a single concept map in the csv format
+var CsvExporter Exporter = ExporterFunc(WriteCsv)
+
+// TsvExporter writes a single concept map in the tsv format
+var TsvExporter Exporter = ExporterFunc(WriteTsv)
@@ -0,0 +1,45 @@
+package conceptmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// LoadFromJsonFile loads a Map from a json file
+func LoadFromJsonFile(file string) ([]*ConceptMap, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return LoadFromJsonReader(f)
+}
+
+// LoadFromJsonReader loads a Map from an io.Reader in json format. The json may
+// be a single concept map, or an array of them
+func LoadFromJsonReader(r io.Reader) ([]*ConceptMap, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := []*definition{}
+
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &defs)
+	} else {
+		def := new(definition)
+		err = json.Unmarshal(trimmed, def)
+		defs = append(defs, def)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return conceptMapsFromDefinitions(defs)
+}
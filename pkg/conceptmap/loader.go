@@ -0,0 +1,187 @@
+package conceptmap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Loader loads concept maps from a reader
+type Loader interface {
+	Load(r io.Reader) ([]*ConceptMap, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface
+type LoaderFunc func(io.Reader) ([]*ConceptMap, error)
+
+func (fn LoaderFunc) Load(r io.Reader) ([]*ConceptMap, error) {
+	return fn(r)
+}
+
+// Format is a file format that concept maps can be written in
+type Format string
+
+const (
+	FormatYaml     Format = "yaml"
+	FormatJson     Format = "json"
+	FormatToml     Format = "toml"
+	FormatMarkdown Format = "markdown"
+	FormatCsv      Format = "csv"
+	FormatTsv      Format = "tsv"
+)
+
+var (
+	YamlLoader     Loader = LoaderFunc(LoadFromYamlReader)
+	JsonLoader     Loader = LoaderFunc(LoadFromJsonReader)
+	TomlLoader     Loader = LoaderFunc(LoadFromTomlReader)
+	MarkdownLoader Loader = LoaderFunc(LoadFromMarkdownReader)
+	CsvLoader      Loader = LoaderFunc(LoadFromCsvReader)
+	TsvLoader      Loader = LoaderFunc(LoadFromTsvReader)
+
+	loaders = map[Format]Loader{
+		FormatYaml:     YamlLoader,
+		FormatJson:     JsonLoader,
+		FormatToml:     TomlLoader,
+		FormatMarkdown: MarkdownLoader,
+		FormatCsv:      CsvLoader,
+		FormatTsv:      TsvLoader,
+	}
+
+	extensions = map[string]Format{
+		".yaml":     FormatYaml,
+		".yml":      FormatYaml,
+		".json":     FormatJson,
+		".toml":     FormatToml,
+		".md":       FormatMarkdown,
+		".markdown": FormatMarkdown,
+		".csv":      FormatCsv,
+		".tsv":      FormatTsv,
+	}
+
+	tomlLinePattern = regexp.MustCompile(`^(\[\[?[A-Za-z0-9_.-]+\]\]?|[A-Za-z0-9_"-]+\s*=)`)
+)
+
+// RegisterFormat adds a format that concept maps can be loaded from, such as one
+// implemented in another package. LoadFromFile uses loader for files with any of
+// extensions, which include the leading dot
+func RegisterFormat(format Format, loader Loader, exts ...string) {
+	loaders[format] = loader
+
+	for _, ext := range exts {
+		extensions[strings.ToLower(ext)] = format
+	}
+}
+
+// LoaderForFormat returns the Loader for format
+func LoaderForFormat(format Format) (Loader, error) {
+	l, ok := loaders[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown concept map format '%s'", format)
+	}
+
+	return l, nil
+}
+
+// DetectFormat returns the format of a concept map file, based on the extension
+// of file, or failing that its content. Content that doesn't look like json, toml,
+// markdown, or csv or tsv with a heading row, is assumed to be yaml
+func DetectFormat(file string, content []byte) Format {
+	if f, ok := extensions[strings.ToLower(filepath.Ext(file))]; ok {
+		return f
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if m := markdownFencePattern.FindStringSubmatch(line); m != nil && strings.TrimSpace(m[2]) == propositionsFence {
+			return FormatMarkdown
+		}
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for f, comma := range map[Format]string{FormatCsv: ",", FormatTsv: "\t"} {
+			if strings.Contains(line, comma) && isHeadingRow(strings.Split(line, comma), propositionColumns) {
+				return f
+			}
+		}
+
+		if strings.HasPrefix(line, "{") {
+			return FormatJson
+		}
+
+		if rest := strings.TrimSpace(strings.TrimPrefix(line, "[")); rest != line && (rest == "" || rest[0] == '{' || rest[0] == ']') {
+			return FormatJson
+		}
+
+		if tomlLinePattern.MatchString(line) {
+			return FormatToml
+		}
+
+		break
+	}
+
+	return FormatYaml
+}
+
+// LoadFromFile loads concept maps from file, in the format detected by
+// DetectFormat. Maps without a title, such as those loaded from csv and tsv files,
+// which can't hold one, are titled with the name of the file. Errors loading the
+// file are prefixed with its name, as untitled maps have no other name yet
+func LoadFromFile(file string) ([]*ConceptMap, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	format := DetectFormat(file, b)
+
+	l, err := LoaderForFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	maps, err := l.Load(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	titleFromFile(maps, file)
+
+	return maps, nil
+}
+
+func conceptMapsFromDefinitions(defs []*definition) ([]*ConceptMap, error) {
+	out := []*ConceptMap{}
+	parser := &PropositionParser{}
+
+	for _, def := range defs {
+		m, err := def.conceptMap(parser)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, m)
+	}
+
+	return out, nil
+}
+
+// titleFromFile titles untitled maps with the name of the file they were loaded
+// from, without its extension
+func titleFromFile(maps []*ConceptMap, file string) {
+	title := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	for _, m := range maps {
+		if m.Title == "" {
+			m.Title = title
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package conceptmap
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	markdownFencePattern   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*(.*)$")
+	markdownHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(\s+#+)?\s*$`)
+)
+
+// propositionsFence is the info string of the fenced code block that holds a
+// markdown concept map's propositions
+const propositionsFence = "propositions"
+
+// LoadFromMarkdownFile loads a Map from a markdown file
+func LoadFromMarkdownFile(file string) ([]*ConceptMap, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return LoadFromMarkdownReader(f)
+}
+
+// LoadFromMarkdownReader loads a Map from an io.Reader in markdown format. The
+// yaml front matter holds the map's title and description, along with any of the
+// fields of the yaml format. Propositions are written in fenced code blocks with
+// the info string propositions, and each level two heading starts the
+// description of the concept with that label. It is an error for a heading not
+// to match the label of a concept in the propositions, as its description would
+// otherwise be lost. Text before the first heading is used as the map's
+// description, and a level one heading as its title, if the front matter doesn't
+// have them
+func LoadFromMarkdownReader(r io.Reader) ([]*ConceptMap, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+	def := new(definition)
+	i := 0
+
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		end := -1
+
+		for j := 1; j < len(lines); j++ {
+			if t := strings.TrimSpace(lines[j]); t == "---" || t == "..." {
+				end = j
+				break
+			}
+		}
+
+		if end < 0 {
+			return nil, fmt.Errorf("markdown front matter is not closed")
+		}
+
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), def); err != nil {
+			return nil, err
+		}
+
+		i = end + 1
+	}
+
+	if def.Concepts == nil {
+		def.Concepts = map[string]*Concept{}
+	}
+
+	propositions := []string{}
+	intro := []string{}
+	sections := map[string][]string{}
+	labels := []string{}
+	label := ""
+	fence := ""
+	inPropositions := false
+
+	appendLine := func(line string) {
+		if label == "" {
+			intro = append(intro, line)
+		} else {
+			sections[label] = append(sections[label], line)
+		}
+	}
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+
+		if fence != "" {
+			closing := strings.TrimSpace(line)
+
+			if strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+				fence = ""
+
+				if inPropositions {
+					inPropositions = false
+					continue
+				}
+			}
+
+			if inPropositions {
+				propositions = append(propositions, line)
+			} else {
+				appendLine(line)
+			}
+
+			continue
+		}
+
+		if m := markdownFencePattern.FindStringSubmatch(line); m != nil {
+			fence = m[1]
+
+			if strings.TrimSpace(m[2]) == propositionsFence {
+				inPropositions = true
+				continue
+			}
+
+			appendLine(line)
+			continue
+		}
+
+		if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil {
+			switch {
+			case len(m[1]) == 1 && label == "" && def.Title == "":
+				def.Title = m[2]
+				continue
+			case len(m[1]) == 2:
+				label = m[2]
+				labels = append(labels, label)
+				continue
+			}
+		}
+
+		appendLine(line)
+	}
+
+	if inPropositions {
+		return nil, fmt.Errorf("fenced %s block is not closed", propositionsFence)
+	}
+
+	def.Propositions = strings.Join(propositions, "\n")
+
+	if strings.TrimSpace(def.Description) == "" {
+		def.Description = strings.TrimSpace(strings.Join(intro, "\n"))
+	}
+
+	for _, l := range labels {
+		description := strings.TrimSpace(strings.Join(sections[l], "\n"))
+		if description == "" {
+			continue
+		}
+
+		c, ok := def.Concepts[l]
+		if !ok || c == nil {
+			c = new(Concept)
+			def.Concepts[l] = c
+		}
+
+		c.Description = description
+	}
+
+	maps, err := conceptMapsFromDefinitions([]*definition{def})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateHeadings(maps[0], labels); err != nil {
+		return nil, err
+	}
+
+	return maps, nil
+}
+
+// validateHeadings returns an error listing the concept headings that don't match
+// the label of a concept in m
+func validateHeadings(m *ConceptMap, labels []string) error {
+	unknown := []string{}
+
+	for _, l := range labels {
+		found := false
+
+		for _, c := range m.Concepts {
+			if c.Label == l {
+				found = true
+				break
+			}
+		}
+
+		if found {
+			continue
+		}
+
+		if c := m.Concept(l); c != nil {
+			unknown = append(unknown, fmt.Sprintf("'%s' (did you mean '%s'?)", l, c.Label))
+		} else {
+			unknown = append(unknown, fmt.Sprintf("'%s'", l))
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("%s has headings that don't match a concept in its propositions: %s", m.describe(), strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
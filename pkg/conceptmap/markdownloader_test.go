@@ -0,0 +1,57 @@
+package conceptmap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadFromMarkdownReaderDescribesConcepts(t *testing.T) {
+	src := "# Kubernetes\n\nRuns containers.\n\n```propositions\nPod runs Container\n```\n\n## Pod\n\nThe smallest unit.\n"
+
+	maps, err := LoadFromMarkdownReader(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := maps[0]
+
+	if m.Title != "Kubernetes" || m.Description != "Runs containers." {
+		t.Errorf("got title %q and description %q", m.Title, m.Description)
+	}
+
+	if c := m.Concept("Pod"); c == nil || c.Description != "The smallest unit." {
+		t.Errorf("expected Pod to be described, got %+v", c)
+	}
+}
+
+func TestLoadFromMarkdownReaderRejectsUnknownHeadings(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "unknown concept",
+			src:  "# Kubernetes\n\n```propositions\nPod runs Container\n```\n\n## Node\n\nA machine.\n",
+			want: "'Node'",
+		},
+		{
+			name: "different label",
+			src:  "# Kubernetes\n\n```propositions\nPod runs Container\n```\n\n## pod\n\nThe smallest unit.\n",
+			want: "'pod' (did you mean 'Pod'?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFromMarkdownReader(strings.NewReader(tt.src))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error %q to mention %s", err, tt.want)
+			}
+		})
+	}
+}
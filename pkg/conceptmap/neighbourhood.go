@@ -0,0 +1,52 @@
+package conceptmap
+
+// Neighbourhood returns the propositions within radius hops of c, ignoring the
+// direction of each proposition, along with the distance in hops of each concept
+// from c, keyed by concept key. Concepts are visited nearest first, and no more
+// than maxNodes concepts are included. A maxNodes of zero or less means no limit.
+// A radius of 1 returns the same propositions as InvolvingConcepts(c)
+func (ps PropositionList) Neighbourhood(c *Concept, radius int, maxNodes int) (PropositionList, map[string]int) {
+	distances := map[string]int{c.Key(): 0}
+	frontier := []string{c.Key()}
+
+	full := func() bool {
+		return maxNodes > 0 && len(distances) >= maxNodes
+	}
+
+	for d := 1; d <= radius && len(frontier) > 0 && !full(); d++ {
+		next := []string{}
+
+		for _, key := range frontier {
+			for _, p := range ps {
+				var other string
+
+				switch key {
+				case p.Left.Key():
+					other = p.Right.Key()
+				case p.Right.Key():
+					other = p.Left.Key()
+				default:
+					continue
+				}
+
+				if _, ok := distances[other]; ok || full() {
+					continue
+				}
+
+				distances[other] = d
+				next = append(next, other)
+			}
+		}
+
+		frontier = next
+	}
+
+	output := ps.Where(func(p *Proposition) bool {
+		l, lok := distances[p.Left.Key()]
+		r, rok := distances[p.Right.Key()]
+
+		return lok && rok && (l < radius || r < radius)
+	})
+
+	return output, distances
+}
@@ -0,0 +1,117 @@
+package conceptmap
+
+import "sort"
+
+// ShortestPath returns the shortest chain of propositions connecting from to to,
+// ignoring the direction of each proposition, or nil if they are not connected
+func (ps PropositionList) ShortestPath(from *Concept, to *Concept) PropositionList {
+	if from.Key() == to.Key() {
+		return PropositionList{}
+	}
+
+	// via records the proposition used to first reach each concept
+	via := map[string]*Proposition{from.Key(): nil}
+	frontier := []string{from.Key()}
+
+	for len(frontier) > 0 {
+		next := []string{}
+
+		for _, key := range frontier {
+			for _, p := range ps {
+				other, ok := otherEnd(p, key)
+				if !ok {
+					continue
+				}
+
+				if _, seen := via[other]; seen {
+					continue
+				}
+
+				via[other] = p
+				next = append(next, other)
+
+				if other == to.Key() {
+					return chainTo(via, from.Key(), other)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return nil
+}
+
+// Paths returns every chain of at most maxLength propositions connecting from to
+// to that visits no concept more than once, ignoring the direction of each
+// proposition. Shorter chains are returned first. A maxLength of zero or less
+// means no limit
+func (ps PropositionList) Paths(from *Concept, to *Concept, maxLength int) []PropositionList {
+	output := []PropositionList{}
+
+	if from.Key() == to.Key() {
+		return output
+	}
+
+	visited := map[string]bool{from.Key(): true}
+	chain := PropositionList{}
+
+	var walk func(key string)
+	walk = func(key string) {
+		if maxLength > 0 && len(chain) >= maxLength {
+			return
+		}
+
+		for _, p := range ps {
+			other, ok := otherEnd(p, key)
+			if !ok || visited[other] {
+				continue
+			}
+
+			chain = append(chain, p)
+
+			if other == to.Key() {
+				output = append(output, append(PropositionList{}, chain...))
+			} else {
+				visited[other] = true
+				walk(other)
+				visited[other] = false
+			}
+
+			chain = chain[:len(chain)-1]
+		}
+	}
+
+	walk(from.Key())
+
+	sort.SliceStable(output, func(i, j int) bool {
+		return len(output[i]) < len(output[j])
+	})
+
+	return output
+}
+
+// otherEnd returns the key of the concept at the other end of p from the concept
+// with key, and false if p doesn't involve that concept
+func otherEnd(p *Proposition, key string) (string, bool) {
+	switch key {
+	case p.Left.Key():
+		return p.Right.Key(), true
+	case p.Right.Key():
+		return p.Left.Key(), true
+	}
+
+	return "", false
+}
+
+func chainTo(via map[string]*Proposition, from string, to string) PropositionList {
+	output := PropositionList{}
+
+	for key := to; key != from; {
+		p := via[key]
+		output = append(PropositionList{p}, output...)
+		key, _ = otherEnd(p, key)
+	}
+
+	return output
+}
@@ -2,10 +2,17 @@ package conceptmap
 
 import (
 	"strings"
+
+	"github.com/gosimple/slug"
 )
 
 type Predicate string
 
+// Slug is the slugified version of the predicate
+func (p Predicate) Slug() string {
+	return slug.Make(string(p))
+}
+
 // Proposition is a phrase consisting of two concepts joined by a predicate
 type Proposition struct {
 	Left      *Concept
@@ -61,6 +68,103 @@ func (ps PropositionList) ConnectingConcepts(cs ...*Concept) PropositionList {
 	})
 }
 
+// InvolvingTag returns the propositions where either concept is tagged with tag
+func (ps PropositionList) InvolvingTag(tag string) PropositionList {
+	return ps.Where(func(p *Proposition) bool {
+		return p.Left.HasTag(tag) || p.Right.HasTag(tag)
+	})
+}
+
+// ConnectingTag returns the propositions where both concepts are tagged with tag
+func (ps PropositionList) ConnectingTag(tag string) PropositionList {
+	return ps.Where(func(p *Proposition) bool {
+		return p.Left.HasTag(tag) && p.Right.HasTag(tag)
+	})
+}
+
+// WithPredicate returns the propositions whose predicate has the same slug as p
+func (ps PropositionList) WithPredicate(p Predicate) PropositionList {
+	return ps.Where(func(o *Proposition) bool {
+		return o.Predicate.Slug() == p.Slug()
+	})
+}
+
+// Predicates returns the distinct predicates used by the propositions, in the
+// order they first appear
+func (ps PropositionList) Predicates() []Predicate {
+	output := []Predicate{}
+	seen := map[string]bool{}
+
+	for _, p := range ps {
+		if !seen[p.Predicate.Slug()] {
+			seen[p.Predicate.Slug()] = true
+			output = append(output, p.Predicate)
+		}
+	}
+
+	return output
+}
+
+// Concepts returns the distinct concepts in the propositions, in the order they
+// first appear
+func (ps PropositionList) Concepts() []*Concept {
+	output := []*Concept{}
+	seen := map[string]bool{}
+
+	for _, p := range ps {
+		for _, c := range []*Concept{p.Left, p.Right} {
+			if !seen[c.Key()] {
+				seen[c.Key()] = true
+				output = append(output, c)
+			}
+		}
+	}
+
+	return output
+}
+
+// ConnectedComponents partitions the concepts in the propositions into sets that
+// are connected to each other, ignoring the direction of each proposition
+func (ps PropositionList) ConnectedComponents() [][]*Concept {
+	parent := map[string]string{}
+
+	var find func(string) string
+	find = func(k string) string {
+		if parent[k] != k {
+			parent[k] = find(parent[k])
+		}
+		return parent[k]
+	}
+
+	concepts := ps.Concepts()
+
+	for _, c := range concepts {
+		parent[c.Key()] = c.Key()
+	}
+
+	for _, p := range ps {
+		parent[find(p.Left.Key())] = find(p.Right.Key())
+	}
+
+	output := [][]*Concept{}
+	index := map[string]int{}
+
+	for _, c := range concepts {
+		root := find(c.Key())
+
+		i, ok := index[root]
+		if !ok {
+			i = len(output)
+			index[root] = i
+			output = append(output, []*Concept{})
+		}
+
+		output[i] = append(output[i], c)
+	}
+
+	return output
+}
+
 func (p *Proposition) String() string {
 	return strings.Join([]string{p.Left.Label, string(p.Predicate), p.Right.Label}, " ")
 }
@@ -26,8 +26,17 @@ func (p *PropositionParser) Parse(s string, propositions *PropositionList, conce
 	return nil
 }
 
+// parseProposition parses a proposition written as a sentence, such as
+// "Kubernetes runs Pods", where concepts start with upper case letters and
+// predicates with lower case letters. Propositions that don't follow this
+// convention can be written explicitly as "Left | predicate | Right"
 func parseProposition(s string, propositions *PropositionList, concepts *[]*Concept) error {
 	trimmed := strings.TrimSpace(s)
+
+	if strings.Contains(trimmed, "|") {
+		return parseExplicitProposition(trimmed, propositions, concepts)
+	}
+
 	words := strings.Fields(trimmed)
 	state := 1 // 1: parsing left concept, 2: parsing predicate, 3 parsing right concept
 	leftWords := []string{}
@@ -72,14 +81,46 @@ func parseProposition(s string, propositions *PropositionList, concepts *[]*Conc
 		return fmt.Errorf("could not find predicate in proposition '%s'", s)
 	}
 
+	appendProposition(strings.Join(leftWords, " "), strings.Join(predicateWords, " "), strings.Join(rightWords, " "), propositions, concepts)
+
+	return nil
+}
+
+func parseExplicitProposition(s string, propositions *PropositionList, concepts *[]*Concept) error {
+	parts := strings.Split(s, "|")
+
+	if len(parts) != 3 {
+		return fmt.Errorf("expected 'Left | predicate | Right' in proposition '%s'", s)
+	}
+
+	for i, part := range parts {
+		parts[i] = strings.Join(strings.Fields(part), " ")
+	}
+
+	if parts[0] == "" {
+		return fmt.Errorf("could not find left concept in proposition '%s'", s)
+	}
+
+	if parts[1] == "" {
+		return fmt.Errorf("could not find predicate in proposition '%s'", s)
+	}
+
+	if parts[2] == "" {
+		return fmt.Errorf("could not find right concept in proposition '%s'", s)
+	}
+
+	appendProposition(parts[0], parts[1], parts[2], propositions, concepts)
+
+	return nil
+}
+
+func appendProposition(leftConceptLabel string, predicate string, rightConceptLabel string, propositions *PropositionList, concepts *[]*Concept) {
 	proposition := &Proposition{
-		Predicate: Predicate(strings.Join(predicateWords, " ")),
+		Predicate: Predicate(predicate),
 	}
 
 	// Check if we have already parsed either the left or right concepts from another
 	// proposition before we create a new one
-	leftConceptLabel := strings.Join(leftWords, " ")
-	rightConceptLabel := strings.Join(rightWords, " ")
 
 	for i, concept := range *concepts {
 		if concept.Label == leftConceptLabel {
@@ -103,8 +144,6 @@ func parseProposition(s string, propositions *PropositionList, concepts *[]*Conc
 	}
 
 	*propositions = append(*propositions, proposition)
-
-	return nil
 }
 
 func startsWithLowerCase(s string) bool {
@@ -0,0 +1,77 @@
+package conceptmap
+
+import "testing"
+
+func TestParseProposition(t *testing.T) {
+	tests := []struct {
+		line                   string
+		left, predicate, right string
+	}{
+		{"Kubernetes runs Pods", "Kubernetes", "runs", "Pods"},
+		{"Container Images are built from Dockerfiles", "Container Images", "are built from", "Dockerfiles"},
+		{"Pods | are scheduled on | worker Nodes", "Pods", "are scheduled on", "worker Nodes"},
+		{"iPhone|is a|smart phone", "iPhone", "is a", "smart phone"},
+		{"  npm  |  Installs  |  node   modules ", "npm", "Installs", "node modules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			ps := PropositionList{}
+			concepts := []*Concept{}
+
+			if err := parseProposition(tt.line, &ps, &concepts); err != nil {
+				t.Fatal(err)
+			}
+
+			p := ps[0]
+
+			if p.Left.Label != tt.left || string(p.Predicate) != tt.predicate || p.Right.Label != tt.right {
+				t.Errorf("got %q | %q | %q", p.Left.Label, p.Predicate, p.Right.Label)
+			}
+		})
+	}
+}
+
+func TestParsePropositionErrors(t *testing.T) {
+	for _, line := range []string{
+		"Kubernetes runs",
+		"runs Pods",
+		"Kubernetes Pods",
+		"Kubernetes runs Pods and containers",
+		"Pods | run",
+		"Pods | run | on | Nodes",
+		" | runs | Pods",
+		"Kubernetes |  | Pods",
+		"Kubernetes | runs | ",
+	} {
+		t.Run(line, func(t *testing.T) {
+			ps := PropositionList{}
+			concepts := []*Concept{}
+
+			if err := parseProposition(line, &ps, &concepts); err == nil {
+				t.Errorf("expected an error, got %v", ps)
+			}
+		})
+	}
+}
+
+func TestPropositionSourceRoundTrips(t *testing.T) {
+	for _, line := range []string{
+		"Kubernetes runs Pods",
+		"Pods | are scheduled on | worker Nodes",
+		"iPhone | is a | smart phone",
+	} {
+		t.Run(line, func(t *testing.T) {
+			ps := PropositionList{}
+			concepts := []*Concept{}
+
+			if err := parseProposition(line, &ps, &concepts); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := PropositionSource(ps[0]); got != line {
+				t.Errorf("got %q, want %q", got, line)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package conceptmap
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gosimple/slug"
+)
+
+// ParseQuery parses a query into a PropositionFilter. A query is a list of terms
+// separated by whitespace, all of which must match a proposition. Each term is
+// written as field:value, which matches values with the same slug, or
+// field~value, which matches values containing value, ignoring case. Fields are
+//
+//   - left, right or concept, which match the label or any alias of the left,
+//     right or either concept
+//   - predicate, which matches the predicate
+//
+// The concept fields can be qualified to match the concept's tags, categories or
+// group instead, as in right:tag:infra. Anything else after the first operator
+// is part of the value, so left:foo:bar matches concepts labelled foo:bar. Values
+// containing spaces can be quoted, as in predicate~"is a". A term without a field
+// matches propositions whose sentence contains it, and a term prefixed with -
+// matches propositions that don't match the rest of the term. An empty query is
+// an error, rather than matching every proposition
+func ParseQuery(query string) (PropositionFilter, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query is empty")
+	}
+
+	terms, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []PropositionFilter{}
+
+	for _, t := range terms {
+		f, err := t.filter()
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, f)
+	}
+
+	return func(p *Proposition) bool {
+		for _, f := range filters {
+			if !f(p) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// Query returns the propositions matching query. See ParseQuery for the syntax
+func (ps PropositionList) Query(query string) (PropositionList, error) {
+	f, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ps.Where(f), nil
+}
+
+type queryTerm struct {
+	negated   bool
+	field     string
+	qualifier string
+	op        rune
+	value     string
+}
+
+func (t *queryTerm) filter() (PropositionFilter, error) {
+	var f PropositionFilter
+
+	switch t.field {
+	case "":
+		f = func(p *Proposition) bool {
+			return strings.Contains(strings.ToLower(p.String()), strings.ToLower(t.value))
+		}
+	case "predicate":
+		if t.qualifier != "" {
+			return nil, fmt.Errorf("predicate can't be qualified with '%s'", t.qualifier)
+		}
+
+		f = func(p *Proposition) bool {
+			return t.match(string(p.Predicate))
+		}
+	case "left", "right", "concept":
+		values, err := t.conceptValues()
+		if err != nil {
+			return nil, err
+		}
+
+		f = func(p *Proposition) bool {
+			switch t.field {
+			case "left":
+				return t.match(values(p.Left)...)
+			case "right":
+				return t.match(values(p.Right)...)
+			}
+			return t.match(values(p.Left)...) || t.match(values(p.Right)...)
+		}
+	default:
+		return nil, fmt.Errorf("unknown query field '%s'", t.field)
+	}
+
+	if t.negated {
+		return func(p *Proposition) bool {
+			return !f(p)
+		}, nil
+	}
+
+	return f, nil
+}
+
+// conceptValues returns a function that gets the values of a concept that the
+// term is matched against
+func (t *queryTerm) conceptValues() (func(*Concept) []string, error) {
+	switch t.qualifier {
+	case "":
+		return func(c *Concept) []string {
+			return append([]string{c.Label}, c.Aliases...)
+		}, nil
+	case "tag":
+		return func(c *Concept) []string {
+			return c.Tags
+		}, nil
+	case "category":
+		return func(c *Concept) []string {
+			return c.Categories
+		}, nil
+	case "group":
+		return func(c *Concept) []string {
+			return []string{c.Group}
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown query qualifier '%s'", t.qualifier)
+}
+
+func (t *queryTerm) match(values ...string) bool {
+	for _, v := range values {
+		if t.op == '~' && strings.Contains(strings.ToLower(v), strings.ToLower(t.value)) {
+			return true
+		}
+
+		if t.op == ':' && v != "" && slug.Make(v) == slug.Make(t.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isQueryQualifier returns true if q is one of the qualifiers of concept fields
+func isQueryQualifier(q string) bool {
+	switch strings.ToLower(q) {
+	case "tag", "category", "group":
+		return true
+	}
+
+	return false
+}
+
+func tokenizeQuery(query string) ([]*queryTerm, error) {
+	terms := []*queryTerm{}
+	rs := []rune(query)
+	i := 0
+
+	// readWord reads up to the next operator, quote or whitespace
+	readWord := func() string {
+		start := i
+		for i < len(rs) && !unicode.IsSpace(rs[i]) && rs[i] != ':' && rs[i] != '~' && rs[i] != '"' {
+			i++
+		}
+		return string(rs[start:i])
+	}
+
+	// readValue reads a quoted string, or up to the next whitespace
+	readValue := func() (string, error) {
+		if i < len(rs) && rs[i] == '"' {
+			var b strings.Builder
+
+			for i++; i < len(rs); i++ {
+				switch {
+				case rs[i] == '\\' && i+1 < len(rs):
+					i++
+					b.WriteRune(rs[i])
+				case rs[i] == '"':
+					i++
+					return b.String(), nil
+				default:
+					b.WriteRune(rs[i])
+				}
+			}
+
+			return "", fmt.Errorf("unterminated quote in query '%s'", query)
+		}
+
+		start := i
+		for i < len(rs) && !unicode.IsSpace(rs[i]) {
+			i++
+		}
+		return string(rs[start:i]), nil
+	}
+
+	for {
+		for i < len(rs) && unicode.IsSpace(rs[i]) {
+			i++
+		}
+
+		if i >= len(rs) {
+			return terms, nil
+		}
+
+		t := &queryTerm{}
+
+		if rs[i] == '-' {
+			t.negated = true
+			i++
+		}
+
+		if i < len(rs) && rs[i] == '"' {
+			value, err := readValue()
+			if err != nil {
+				return nil, err
+			}
+
+			t.value = value
+		} else {
+			word := readWord()
+
+			if i < len(rs) && (rs[i] == ':' || rs[i] == '~') {
+				if word == "" {
+					return nil, fmt.Errorf("missing field in query '%s'", query)
+				}
+
+				t.field = strings.ToLower(word)
+				t.op = rs[i]
+				i++
+
+				// A concept field may be qualified, as in right:tag:infra
+				start := i
+				if q := readWord(); i < len(rs) && rs[i] == ':' && isQueryQualifier(q) {
+					t.qualifier = strings.ToLower(q)
+					i++
+				} else {
+					i = start
+				}
+
+				value, err := readValue()
+				if err != nil {
+					return nil, err
+				}
+
+				t.value = value
+			} else {
+				t.value = word
+			}
+		}
+
+		if t.value == "" {
+			return nil, fmt.Errorf("missing value in query '%s'", query)
+		}
+
+		terms = append(terms, t)
+	}
+}
@@ -0,0 +1,118 @@
+package conceptmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []queryTerm
+	}{
+		{"pod", []queryTerm{{value: "pod"}}},
+		{"left:pod", []queryTerm{{field: "left", op: ':', value: "pod"}}},
+		{"predicate~runs", []queryTerm{{field: "predicate", op: '~', value: "runs"}}},
+		{"Left:Pod", []queryTerm{{field: "left", op: ':', value: "Pod"}}},
+		{"-right:node", []queryTerm{{negated: true, field: "right", op: ':', value: "node"}}},
+		{`predicate~"is a"`, []queryTerm{{field: "predicate", op: '~', value: "is a"}}},
+		{`"runs on"`, []queryTerm{{value: "runs on"}}},
+		{`left:"say \"hi\""`, []queryTerm{{field: "left", op: ':', value: `say "hi"`}}},
+		{"right:tag:infra", []queryTerm{{field: "right", qualifier: "tag", op: ':', value: "infra"}}},
+		{"concept~Category:net", []queryTerm{{field: "concept", qualifier: "category", op: '~', value: "net"}}},
+		{"left:group:core", []queryTerm{{field: "left", qualifier: "group", op: ':', value: "core"}}},
+		{`left:tag:"two words"`, []queryTerm{{field: "left", qualifier: "tag", op: ':', value: "two words"}}},
+		{"left:foo:bar", []queryTerm{{field: "left", op: ':', value: "foo:bar"}}},
+		{"left:foo:bar:baz", []queryTerm{{field: "left", op: ':', value: "foo:bar:baz"}}},
+		{"left~a~b", []queryTerm{{field: "left", op: '~', value: "a~b"}}},
+		{"  left:pod \t predicate~runs  ", []queryTerm{
+			{field: "left", op: ':', value: "pod"},
+			{field: "predicate", op: '~', value: "runs"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			terms, err := tokenizeQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []queryTerm{}
+			for _, term := range terms {
+				got = append(got, *term)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		":pod",
+		"left:",
+		`left:"pod`,
+		"-",
+	} {
+		t.Run(query, func(t *testing.T) {
+			if _, err := tokenizeQuery(query); err == nil {
+				t.Errorf("expected an error for query %q", query)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"   ",
+		"\t\n",
+		"colour:red",
+		"predicate:tag:infra",
+	} {
+		t.Run(query, func(t *testing.T) {
+			if _, err := ParseQuery(query); err == nil {
+				t.Errorf("expected an error for query %q", query)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	pod := &Concept{Label: "Pod", Tags: []string{"Workloads"}}
+	node := &Concept{Label: "Node"}
+	url := &Concept{Label: "http://example.com"}
+
+	ps := PropositionList{
+		{Left: pod, Predicate: "runs on", Right: node},
+		{Left: node, Predicate: "is found at", Right: url},
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"left:pod", 1},
+		{"concept:node", 2},
+		{"-concept:pod", 1},
+		{"left:tag:workloads", 1},
+		{`predicate~"runs"`, 1},
+		{"right:http://example.com", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := ps.Query(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != tt.want {
+				t.Errorf("got %d propositions, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
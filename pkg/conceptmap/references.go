@@ -0,0 +1,97 @@
+package conceptmap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gosimple/slug"
+)
+
+var conceptReferencePattern = regexp.MustCompile(`\[\[([^\[\]|]+)(?:\|([^\[\]]+))?\]\]`)
+
+// ConceptReference is a wiki style reference to a concept, written as [[Label]] or
+// [[Label|text]] in a description
+type ConceptReference struct {
+	// Label is the label of the referenced concept
+	Label string
+
+	// Text is the text to display in place of the reference
+	Text string
+}
+
+// Key is the normalised key of the referenced concept
+func (r *ConceptReference) Key() string {
+	return slug.Make(r.Label)
+}
+
+// ReplaceConceptReferences replaces every concept reference in s with the result
+// of calling fn
+func ReplaceConceptReferences(s string, fn func(*ConceptReference) string) string {
+	return conceptReferencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		return fn(parseConceptReference(match))
+	})
+}
+
+// FindConceptReferences returns all concept references in s
+func FindConceptReferences(s string) []*ConceptReference {
+	output := []*ConceptReference{}
+
+	for _, match := range conceptReferencePattern.FindAllString(s, -1) {
+		output = append(output, parseConceptReference(match))
+	}
+
+	return output
+}
+
+// ValidateReferences returns an error describing every concept reference in the
+// descriptions of the map and its concepts that does not resolve to a concept in
+// the map
+func (m *ConceptMap) ValidateReferences() error {
+	unknown := []string{}
+
+	check := func(description, where string) {
+		for _, ref := range FindConceptReferences(description) {
+			if m.Concept(ref.Label) == nil {
+				unknown = append(unknown, fmt.Sprintf("[[%s]] in %s", ref.Label, where))
+			}
+		}
+	}
+
+	check(m.Description, "map description")
+
+	for _, c := range m.Concepts {
+		check(c.Description, fmt.Sprintf("description of '%s'", c.Label))
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("%s has unknown concept references: %s", m.describe(), strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// describe names m in error messages. Maps loaded from files without a title
+// are only titled after they are loaded, so may not have one yet
+func (m *ConceptMap) describe() string {
+	if m.Title == "" {
+		return "untitled concept map"
+	}
+
+	return fmt.Sprintf("concept map '%s'", m.Title)
+}
+
+func parseConceptReference(s string) *ConceptReference {
+	groups := conceptReferencePattern.FindStringSubmatch(s)
+	label := strings.TrimSpace(groups[1])
+	text := strings.TrimSpace(groups[2])
+
+	if text == "" {
+		text = label
+	}
+
+	return &ConceptReference{
+		Label: label,
+		Text:  text,
+	}
+}
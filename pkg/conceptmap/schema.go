@@ -0,0 +1,9 @@
+package conceptmap
+
+import _ "embed"
+
+// JSONSchema is a json schema describing the concept map definition format, which
+// editors can use to validate and autocomplete concept map files
+//
+//go:embed schema.json
+var JSONSchema []byte
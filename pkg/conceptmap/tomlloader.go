@@ -0,0 +1,43 @@
+package conceptmap
+
+import (
+	"io"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlDocument allows a toml file to contain several concept maps, as an array
+// of [[maps]] tables, since a toml document can't be an array
+type tomlDocument struct {
+	definition
+	Maps []*definition `toml:"maps"`
+}
+
+// LoadFromTomlFile loads a Map from a toml file
+func LoadFromTomlFile(file string) ([]*ConceptMap, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return LoadFromTomlReader(f)
+}
+
+// LoadFromTomlReader loads a Map from an io.Reader in toml format. The toml may
+// be a single concept map, or contain several as an array of [[maps]] tables
+func LoadFromTomlReader(r io.Reader) ([]*ConceptMap, error) {
+	doc := new(tomlDocument)
+
+	if _, err := toml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Maps) > 0 {
+		return conceptMapsFromDefinitions(doc.Maps)
+	}
+
+	return conceptMapsFromDefinitions([]*definition{&doc.definition})
+}
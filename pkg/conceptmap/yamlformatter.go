@@ -0,0 +1,411 @@
+package conceptmap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWrapWidth is the width that descriptions are wrapped to, unless another
+// is set with WithWrapWidth
+const DefaultWrapWidth = 80
+
+// Order of the keys of maps, and of the details of concepts, in formatted yaml.
+// Keys that aren't listed keep their order, after those that are
+var (
+	mapKeyOrder     = []string{"title", "description", "propositions", "concepts"}
+	conceptKeyOrder = []string{"label", "aliases", "description", "isKeyConcept", "tags", "categories", "group"}
+
+	// markdownBlockPattern matches lines that start markdown blocks, such as lists
+	// and headings, whose line breaks are kept when wrapping descriptions
+	markdownBlockPattern = regexp.MustCompile("^(\\s{4}|\\t|[-*+]\\s|>|\\||#{1,6}(\\s|$)|\\d+[.)]\\s|```|~~~)")
+
+	// setextUnderlinePattern matches the line under a setext heading, which makes
+	// the paragraph above it a heading
+	setextUnderlinePattern = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+)
+
+// YamlFormatter rewrites concept map yaml in a canonical form, keeping its
+// comments
+type YamlFormatter struct {
+	width int
+}
+
+type YamlFormatterOption func(*YamlFormatter)
+
+// WithWrapWidth sets the width that description lines are wrapped to, including
+// their indentation. Long words are never broken
+func WithWrapWidth(width int) YamlFormatterOption {
+	return func(f *YamlFormatter) {
+		f.width = width
+	}
+}
+
+func NewYamlFormatter(opts ...YamlFormatterOption) *YamlFormatter {
+	f := &YamlFormatter{
+		width: DefaultWrapWidth,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Format returns src in canonical form. Documents are ordered by title, and the
+// keys of each map in the order title, description, propositions then concepts.
+// Each proposition is written on a single line, as PropositionSource writes it,
+// and every concept in the propositions is given an entry in the concepts
+// section, in the order they first appear. Descriptions are wrapped to the wrap
+// width, except for lines that start markdown blocks such as lists
+func (f *YamlFormatter) Format(src []byte) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(src))
+	docs := []*yaml.Node{}
+
+	for {
+		doc := new(yaml.Node)
+
+		if err := dec.Decode(doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if len(doc.Content) == 0 {
+			continue
+		}
+
+		if root := doc.Content[0]; root.Kind == yaml.MappingNode {
+			if err := f.formatMap(root); err != nil {
+				if title := mappingValue(root, "title"); title != nil {
+					return nil, fmt.Errorf("%s: %w", title.Value, err)
+				}
+				return nil, err
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		return strings.ToLower(documentTitle(docs[i])) < strings.ToLower(documentTitle(docs[j]))
+	})
+
+	var b bytes.Buffer
+
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func (f *YamlFormatter) formatMap(node *yaml.Node) error {
+	sortMappingKeys(node, mapKeyOrder)
+
+	if d := mappingValue(node, "description"); d != nil {
+		f.wrapScalar(d, 2)
+	}
+
+	concepts := []*Concept{}
+
+	if p := mappingValue(node, "propositions"); p != nil && p.Kind == yaml.ScalarNode {
+		parsed := PropositionList{}
+		lines := []string{}
+
+		for _, line := range strings.Split(p.Value, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			if err := parseProposition(line, &parsed, &concepts); err != nil {
+				return err
+			}
+
+			lines = append(lines, PropositionSource(parsed[len(parsed)-1]))
+		}
+
+		p.Kind, p.Tag, p.Style = yaml.ScalarNode, "!!str", yaml.LiteralStyle
+		p.Value = strings.Join(lines, "\n") + "\n"
+	}
+
+	if len(concepts) == 0 && mappingValue(node, "concepts") == nil {
+		return nil
+	}
+
+	section := mappingValue(node, "concepts")
+
+	if section == nil || section.Kind != yaml.MappingNode {
+		if section != nil && !isNull(section) {
+			return fmt.Errorf("concepts must be a mapping of concept labels to details")
+		}
+
+		if section == nil {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "concepts"},
+				&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+			section = node.Content[len(node.Content)-1]
+		} else {
+			section.Kind, section.Tag, section.Value = yaml.MappingNode, "!!map", ""
+		}
+	}
+
+	f.formatConcepts(section, concepts)
+
+	return nil
+}
+
+// formatConcepts orders the entries of the concepts section by where their
+// concept first appears in the propositions, adding entries for concepts that
+// don't have one. Entries for concepts that aren't in the propositions are kept
+// after the rest
+func (f *YamlFormatter) formatConcepts(section *yaml.Node, concepts []*Concept) {
+	entries := map[string][2]*yaml.Node{}
+	unused := []*yaml.Node{}
+
+	for i := 0; i+1 < len(section.Content); i += 2 {
+		k, v := section.Content[i], section.Content[i+1]
+
+		if _, ok := entries[k.Value]; ok || !containsLabel(concepts, k.Value) {
+			unused = append(unused, k, v)
+			continue
+		}
+
+		entries[k.Value] = [2]*yaml.Node{k, v}
+	}
+
+	content := []*yaml.Node{}
+
+	for _, c := range concepts {
+		entry, ok := entries[c.Label]
+		if !ok {
+			entry = [2]*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: c.Label},
+				{Kind: yaml.MappingNode, Tag: "!!map"},
+			}
+		}
+
+		content = append(content, entry[0], entry[1])
+	}
+
+	section.Content = append(content, unused...)
+
+	for i := 1; i < len(section.Content); i += 2 {
+		details := section.Content[i]
+
+		if isNull(details) {
+			details.Kind, details.Tag, details.Value = yaml.MappingNode, "!!map", ""
+		}
+
+		if details.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if mappingValue(details, "description") == nil {
+			details.Content = append(details.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "description"},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ""})
+		}
+
+		sortMappingKeys(details, conceptKeyOrder)
+
+		f.wrapScalar(mappingValue(details, "description"), 6)
+	}
+}
+
+// wrapScalar wraps the text of a scalar node written at indent, writing it as a
+// literal block if it spans several lines. The yaml encoder won't write trailing
+// spaces in a literal block, so text with hard line breaks is double quoted
+func (f *YamlFormatter) wrapScalar(node *yaml.Node, indent int) {
+	if node == nil || node.Kind != yaml.ScalarNode || node.Tag != "!!str" && node.Tag != "" {
+		return
+	}
+
+	text := wrapText(node.Value, f.width-indent)
+
+	node.Value = text
+	node.Style = 0
+
+	switch {
+	case strings.Contains(text, hardBreak+"\n"):
+		node.Value += "\n"
+		node.Style = yaml.DoubleQuotedStyle
+	case strings.Contains(text, "\n"):
+		node.Value += "\n"
+		node.Style = yaml.LiteralStyle
+	}
+}
+
+// wrapText reflows each paragraph of s to width, leaving paragraphs that hold
+// markdown blocks, such as lists and setext headings, or are within fenced code
+// blocks, as they are. Hard line breaks, written as two trailing spaces, are kept
+func wrapText(s string, width int) string {
+	paragraphs := []string{}
+	fenced := false
+
+	for _, p := range strings.Split(strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n")), "\n\n") {
+		lines := strings.Split(strings.Trim(p, "\n"), "\n")
+
+		if len(lines) == 1 && strings.TrimSpace(lines[0]) == "" {
+			continue
+		}
+
+		block := fenced
+
+		for i, l := range lines {
+			lines[i] = trimLine(l)
+
+			if markdownBlockPattern.MatchString(l) || setextUnderlinePattern.MatchString(l) {
+				block = true
+			}
+
+			if strings.HasPrefix(strings.TrimSpace(l), "```") || strings.HasPrefix(strings.TrimSpace(l), "~~~") {
+				fenced = !fenced
+			}
+		}
+
+		// A hard break at the end of a paragraph doesn't break anything
+		lines[len(lines)-1] = strings.TrimRight(lines[len(lines)-1], " \t")
+
+		if block {
+			paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+			continue
+		}
+
+		// Each run of lines up to a hard break is wrapped on its own
+		wrapped := []string{}
+		start := 0
+
+		for i, l := range lines {
+			if strings.HasSuffix(l, hardBreak) || i == len(lines)-1 {
+				wrapped = append(wrapped, wrapWords(strings.Join(lines[start:i+1], " "), width))
+				start = i + 1
+			}
+		}
+
+		paragraphs = append(paragraphs, strings.Join(wrapped, hardBreak+"\n"))
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// hardBreak ends a line of markdown that is broken where it is written
+const hardBreak = "  "
+
+// trimLine removes the whitespace at the end of a line, other than a hard break
+func trimLine(l string) string {
+	trimmed := strings.TrimRight(l, " \t")
+
+	if strings.HasSuffix(l, hardBreak) && strings.TrimSpace(trimmed) != "" {
+		return trimmed + hardBreak
+	}
+
+	return trimmed
+}
+
+// wrapWords joins the words of s into lines no longer than width, where the
+// words allow
+func wrapWords(s string, width int) string {
+	var b strings.Builder
+	n := 0
+
+	for _, word := range strings.Fields(s) {
+		switch {
+		case n == 0:
+		case n+1+len(word) > width:
+			b.WriteString("\n")
+			n = 0
+		default:
+			b.WriteString(" ")
+			n++
+		}
+
+		b.WriteString(word)
+		n += len(word)
+	}
+
+	return b.String()
+}
+
+// sortMappingKeys orders the keys of a mapping node by their position in order,
+// keeping the order of keys that aren't in it after those that are
+func sortMappingKeys(node *yaml.Node, order []string) {
+	type pair struct{ key, value *yaml.Node }
+
+	pairs := []pair{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+
+	rank := func(key string) int {
+		for i, k := range order {
+			if k == key {
+				return i
+			}
+		}
+		return len(order)
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return rank(pairs[i].key.Value) < rank(pairs[j].key.Value)
+	})
+
+	node.Content = node.Content[:0]
+
+	for _, p := range pairs {
+		node.Content = append(node.Content, p.key, p.value)
+	}
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func documentTitle(doc *yaml.Node) string {
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		if t := mappingValue(doc.Content[0], "title"); t != nil {
+			return t.Value
+		}
+	}
+
+	return ""
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func containsLabel(concepts []*Concept, label string) bool {
+	for _, c := range concepts {
+		if c.Label == label {
+			return true
+		}
+	}
+
+	return false
+}
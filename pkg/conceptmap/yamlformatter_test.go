@@ -0,0 +1,188 @@
+package conceptmap
+
+import (
+	"strings"
+	"testing"
+)
+
+func format(t *testing.T, src string, opts ...YamlFormatterOption) string {
+	t.Helper()
+
+	out, err := NewYamlFormatter(opts...).Format([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(out)
+}
+
+func TestFormatIsIdempotent(t *testing.T) {
+	for name, src := range map[string]string{
+		"several maps": "title: Kubernetes\npropositions: |\n  Kubernetes runs Pods\n---\ntitle: Docker\npropositions: Docker builds Images\n",
+		"comments":     "# Maps\ntitle: Kubernetes # the title\npropositions: |\n  Kubernetes runs Pods\nconcepts:\n  # Described\n  Pods:\n    description: The smallest unit\n",
+		"explicit":     "title: Phones\npropositions: |\n  iPhone | is a | smart phone\n",
+		"markdown":     "title: T\ndescription: |\n  Overview\n  --------\n\n  - one\n  - two\n\n  First line  \n  second line\npropositions: A runs B\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			once := format(t, src, WithWrapWidth(40))
+
+			if twice := format(t, once, WithWrapWidth(40)); twice != once {
+				t.Errorf("formatting again changed\n%s\nto\n%s", once, twice)
+			}
+		})
+	}
+}
+
+func TestFormatKeepsComments(t *testing.T) {
+	out := format(t, `# Maps about containers
+title: Kubernetes # the title
+propositions: |
+  Kubernetes runs Pods
+concepts:
+  # Only pods are described
+  Pods:
+    description: The smallest unit # a short description
+`)
+
+	for _, comment := range []string{"# Maps about containers", "# the title", "# Only pods are described", "# a short description"} {
+		if !strings.Contains(out, comment) {
+			t.Errorf("expected %q to be kept in\n%s", comment, out)
+		}
+	}
+}
+
+func TestFormatKeepsHardLineBreaks(t *testing.T) {
+	out := format(t, "title: T\npropositions: A runs B\nconcepts:\n  B:\n    description: \"a  \\nb\"\n")
+
+	maps, err := LoadFromYamlReader(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.TrimSuffix(maps[0].Concept("B").Description, "\n"); got != "a  \nb" {
+		t.Errorf("expected the description to keep its hard line break, got %q from\n%s", got, out)
+	}
+}
+
+func TestFormatOrdersKeysAndMaps(t *testing.T) {
+	out := format(t, "propositions: B runs C\ntitle: beta\n---\ntitle: Alpha\npropositions: A runs B\n")
+
+	want := `title: Alpha
+propositions: |
+  A runs B
+concepts:
+  A:
+    description: ""
+  B:
+    description: ""
+---
+title: beta
+propositions: |
+  B runs C
+concepts:
+  B:
+    description: ""
+  C:
+    description: ""
+`
+
+	if out != want {
+		t.Errorf("got\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestFormatAddsConceptEntries(t *testing.T) {
+	out := format(t, `title: Kubernetes
+propositions: |
+  Kubernetes runs Pods
+  Pods contain Containers
+concepts:
+  Ghost:
+    description: Not used anywhere
+  Pods:
+    isKeyConcept: true
+    description: The smallest unit
+`)
+
+	want := `title: Kubernetes
+propositions: |
+  Kubernetes runs Pods
+  Pods contain Containers
+concepts:
+  Kubernetes:
+    description: ""
+  Pods:
+    description: The smallest unit
+    isKeyConcept: true
+  Containers:
+    description: ""
+  Ghost:
+    description: Not used anywhere
+`
+
+	if out != want {
+		t.Errorf("got\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "paragraph",
+			text: "one two three\nfour five six seven",
+			want: "one two three four\nfive six seven",
+		},
+		{
+			name: "long word",
+			text: "a supercalifragilistic word",
+			want: "a\nsupercalifragilistic\nword",
+		},
+		{
+			name: "list",
+			text: "- one two three four five\n- six",
+			want: "- one two three four five\n- six",
+		},
+		{
+			name: "atx heading",
+			text: "# Heading\ntext",
+			want: "# Heading\ntext",
+		},
+		{
+			name: "setext heading",
+			text: "Overview\n--------\n\nSome text",
+			want: "Overview\n--------\n\nSome text",
+		},
+		{
+			name: "setext heading with equals",
+			text: "Overview\n===",
+			want: "Overview\n===",
+		},
+		{
+			name: "fenced code",
+			text: "```\ncode   here\n\nmore code\n```\n\nafter",
+			want: "```\ncode   here\n\nmore code\n```\n\nafter",
+		},
+		{
+			name: "hard breaks",
+			text: "one two three four five  \nsix\nseven  \neight  ",
+			want: "one two three four\nfive  \nsix seven  \neight",
+		},
+		{
+			name: "trailing whitespace",
+			text: "one \t\ntwo\t\n\n\n\nthree ",
+			want: "one two\n\nthree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapText(tt.text, 18); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
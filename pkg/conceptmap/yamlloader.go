@@ -8,13 +8,6 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-type yamlDefinition struct {
-	Title        string              `yaml:"title"`
-	Description  string              `yaml:"description"`
-	Propositions string              `yaml:"propositions"`
-	Concepts     map[string]*Concept `yaml:"concepts"`
-}
-
 // LoadFromYamlFile loads a Map from a yaml file
 func LoadFromYamlFile(file string) ([]*ConceptMap, error) {
 	f, err := os.Open(file)
@@ -34,7 +27,7 @@ func LoadFromYamlReader(r io.Reader) ([]*ConceptMap, error) {
 	parser := &PropositionParser{}
 
 	for {
-		def := new(yamlDefinition)
+		def := new(definition)
 
 		err := dec.Decode(def)
 
@@ -46,26 +39,11 @@ func LoadFromYamlReader(r io.Reader) ([]*ConceptMap, error) {
 			}
 		}
 
-		m := &ConceptMap{
-			Title:        def.Title,
-			Description:  def.Description,
-			Concepts:     []*Concept{},
-			Propositions: []*Proposition{},
-		}
-
-		if err := parser.Parse(def.Propositions, &m.Propositions, &m.Concepts); err != nil {
+		m, err := def.conceptMap(parser)
+		if err != nil {
 			return nil, err
 		}
 
-		for k, v := range def.Concepts {
-			for _, c := range m.Concepts {
-				if c.Label == k {
-					c.Description = v.Description
-					c.IsKeyConcept = v.IsKeyConcept
-				}
-			}
-		}
-
 		out = append(out, m)
 	}
 
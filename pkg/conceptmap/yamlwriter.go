@@ -0,0 +1,116 @@
+package conceptmap
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlConceptDetails are the details of a concept written to the concepts section
+// of the yaml format
+type yamlConceptDetails struct {
+	Aliases      []string `yaml:"aliases,omitempty"`
+	Description  string   `yaml:"description,omitempty"`
+	IsKeyConcept bool     `yaml:"isKeyConcept,omitempty"`
+	Tags         []string `yaml:"tags,omitempty"`
+	Categories   []string `yaml:"categories,omitempty"`
+	Group        string   `yaml:"group,omitempty"`
+}
+
+type yamlMapDefinition struct {
+	Title        string     `yaml:"title"`
+	Description  string     `yaml:"description,omitempty"`
+	Propositions *yaml.Node `yaml:"propositions"`
+	Concepts     *yaml.Node `yaml:"concepts,omitempty"`
+}
+
+// WriteYaml writes maps to w in the yaml format, as separate documents
+func WriteYaml(w io.Writer, maps []*ConceptMap) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	for _, m := range maps {
+		def, err := newYamlMapDefinition(m)
+		if err != nil {
+			return err
+		}
+
+		if err := enc.Encode(def); err != nil {
+			return err
+		}
+	}
+
+	return enc.Close()
+}
+
+func newYamlMapDefinition(m *ConceptMap) (*yamlMapDefinition, error) {
+	lines := []string{}
+
+	for _, p := range m.Propositions {
+		lines = append(lines, PropositionSource(p))
+	}
+
+	def := &yamlMapDefinition{
+		Title:       m.Title,
+		Description: m.Description,
+		Propositions: &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Style: yaml.LiteralStyle,
+			Value: strings.Join(lines, "\n") + "\n",
+		},
+	}
+
+	concepts := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, c := range m.Concepts {
+		details := &yamlConceptDetails{
+			Aliases:      c.Aliases,
+			Description:  c.Description,
+			IsKeyConcept: c.IsKeyConcept,
+			Tags:         c.Tags,
+			Categories:   c.Categories,
+			Group:        c.Group,
+		}
+
+		if details.isEmpty() {
+			continue
+		}
+
+		value := new(yaml.Node)
+		if err := value.Encode(details); err != nil {
+			return nil, err
+		}
+
+		concepts.Content = append(concepts.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: c.Label}, value)
+	}
+
+	if len(concepts.Content) > 0 {
+		def.Concepts = concepts
+	}
+
+	return def, nil
+}
+
+func (d *yamlConceptDetails) isEmpty() bool {
+	return len(d.Aliases) == 0 && d.Description == "" && !d.IsKeyConcept && len(d.Tags) == 0 && len(d.Categories) == 0 && d.Group == ""
+}
+
+// PropositionSource returns p as it would be written in the propositions of a
+// concept map, as a sentence if it can be parsed back to the same proposition,
+// or in the explicit "Left | predicate | Right" form if not
+func PropositionSource(p *Proposition) string {
+	sentence := p.String()
+
+	parsed := PropositionList{}
+	concepts := []*Concept{}
+
+	if err := parseProposition(sentence, &parsed, &concepts); err == nil && len(parsed) == 1 &&
+		parsed[0].Left.Label == p.Left.Label &&
+		parsed[0].Predicate == p.Predicate &&
+		parsed[0].Right.Label == p.Right.Label {
+		return sentence
+	}
+
+	return strings.Join([]string{p.Left.Label, string(p.Predicate), p.Right.Label}, " | ")
+}
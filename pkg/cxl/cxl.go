@@ -0,0 +1,182 @@
+// Package cxl imports and exports concept maps in the CXL format used by IHMC
+// CmapTools.
+//
+// A CXL map is made up of concepts, linking phrases and connections. Each
+// connection joins a concept to a linking phrase, or a linking phrase to a
+// concept, and every combination of a concept connected to a linking phrase and a
+// concept that the linking phrase connects to becomes a proposition. Concepts
+// that aren't connected to anything are not imported, as concept maps only
+// contain the concepts in their propositions. Connections between two linking
+// phrases have no proposition to become, so are an error
+package cxl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// Format is the conceptmap format name of CXL files
+const Format conceptmap.Format = "cxl"
+
+// Loader loads concept maps from CXL files
+var Loader conceptmap.Loader = conceptmap.LoaderFunc(Load)
+
+// CXL files are loaded by conceptmap.LoadFromFile in any program that imports
+// this package
+func init() {
+	conceptmap.RegisterFormat(Format, Loader, ".cxl")
+}
+
+// defaultPredicate is used for connections directly between two concepts, and
+// linking phrases without a label
+const defaultPredicate = "relates to"
+
+type document struct {
+	XMLName xml.Name `xml:"cmap"`
+	Meta    struct {
+		Title       string `xml:"title"`
+		Description string `xml:"description"`
+	} `xml:"res-meta"`
+	Map struct {
+		Concepts       []element    `xml:"concept-list>concept"`
+		LinkingPhrases []element    `xml:"linking-phrase-list>linking-phrase"`
+		Connections    []connection `xml:"connection-list>connection"`
+	} `xml:"map"`
+}
+
+type element struct {
+	ID           string `xml:"id,attr"`
+	Label        string `xml:"label,attr"`
+	ShortComment string `xml:"short-comment,attr,omitempty"`
+	LongComment  string `xml:"long-comment,attr,omitempty"`
+}
+
+type connection struct {
+	ID     string `xml:"id,attr"`
+	FromID string `xml:"from-id,attr"`
+	ToID   string `xml:"to-id,attr"`
+}
+
+// Load loads a concept map from r in CXL format
+func Load(r io.Reader) ([]*conceptmap.ConceptMap, error) {
+	doc := new(document)
+
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("could not read cxl: %w", err)
+	}
+
+	m := &conceptmap.ConceptMap{
+		Title:        normaliseLabel(doc.Meta.Title),
+		Description:  strings.TrimSpace(doc.Meta.Description),
+		Concepts:     []*conceptmap.Concept{},
+		Propositions: conceptmap.PropositionList{},
+	}
+
+	elements := map[string]element{}
+	phrases := map[string]bool{}
+
+	for _, c := range doc.Map.Concepts {
+		elements[c.ID] = c
+	}
+
+	for _, p := range doc.Map.LinkingPhrases {
+		elements[p.ID] = p
+		phrases[p.ID] = true
+	}
+
+	// Collect the concepts connected to, and from, each linking phrase
+	sources := map[string][]string{}
+	targets := map[string][]string{}
+	phraseOrder := []string{}
+
+	for _, conn := range doc.Map.Connections {
+		if _, ok := elements[conn.FromID]; !ok {
+			return nil, fmt.Errorf("connection '%s' is from unknown element '%s'", conn.ID, conn.FromID)
+		}
+
+		if _, ok := elements[conn.ToID]; !ok {
+			return nil, fmt.Errorf("connection '%s' is to unknown element '%s'", conn.ID, conn.ToID)
+		}
+
+		switch {
+		case phrases[conn.ToID] && !phrases[conn.FromID]:
+			if len(sources[conn.ToID]) == 0 && len(targets[conn.ToID]) == 0 {
+				phraseOrder = append(phraseOrder, conn.ToID)
+			}
+			sources[conn.ToID] = append(sources[conn.ToID], conn.FromID)
+
+		case phrases[conn.FromID] && !phrases[conn.ToID]:
+			if len(sources[conn.FromID]) == 0 && len(targets[conn.FromID]) == 0 {
+				phraseOrder = append(phraseOrder, conn.FromID)
+			}
+			targets[conn.FromID] = append(targets[conn.FromID], conn.ToID)
+
+		case !phrases[conn.FromID] && !phrases[conn.ToID]:
+			appendProposition(m, elements[conn.FromID], defaultPredicate, elements[conn.ToID])
+
+		default:
+			return nil, fmt.Errorf("connection '%s' joins linking phrase '%s' to linking phrase '%s', which can't be written as a proposition",
+				conn.ID, normaliseLabel(elements[conn.FromID].Label), normaliseLabel(elements[conn.ToID].Label))
+		}
+	}
+
+	for _, id := range phraseOrder {
+		predicate := normalisePredicate(elements[id].Label)
+
+		for _, from := range sources[id] {
+			for _, to := range targets[id] {
+				appendProposition(m, elements[from], predicate, elements[to])
+			}
+		}
+	}
+
+	return []*conceptmap.ConceptMap{m}, nil
+}
+
+func appendProposition(m *conceptmap.ConceptMap, left element, predicate string, right element) {
+	m.Propositions = append(m.Propositions, &conceptmap.Proposition{
+		Left:      concept(m, left),
+		Predicate: conceptmap.Predicate(predicate),
+		Right:     concept(m, right),
+	})
+}
+
+// concept returns the concept in m for e, adding it to m if it hasn't been added
+// already
+func concept(m *conceptmap.ConceptMap, e element) *conceptmap.Concept {
+	label := normaliseLabel(e.Label)
+
+	for _, c := range m.Concepts {
+		if c.Label == label {
+			return c
+		}
+	}
+
+	c := &conceptmap.Concept{
+		Label:       label,
+		Description: strings.TrimSpace(strings.Join([]string{e.ShortComment, e.LongComment}, "\n\n")),
+	}
+
+	m.Concepts = append(m.Concepts, c)
+
+	return c
+}
+
+// normaliseLabel collapses the line breaks and runs of whitespace that CmapTools
+// uses to lay out labels, and replaces the | character, which separates the parts
+// of an explicit proposition
+func normaliseLabel(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", "/")
+}
+
+func normalisePredicate(s string) string {
+	if p := normaliseLabel(s); p != "" {
+		return p
+	}
+
+	return defaultPredicate
+}
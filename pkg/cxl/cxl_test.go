@@ -0,0 +1,104 @@
+package cxl
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// cxlMap returns a CXL document with the given concepts, linking phrases and
+// connections, each written as id=label or from>to
+func cxlMap(concepts, phrases, connections []string) string {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><cmap xmlns="http://cmap.ihmc.us/xml/cmap/"><map><concept-list>`)
+
+	for _, c := range concepts {
+		parts := strings.SplitN(c, "=", 2)
+		b.WriteString(`<concept id="` + parts[0] + `" label="` + parts[1] + `"/>`)
+	}
+
+	b.WriteString(`</concept-list><linking-phrase-list>`)
+
+	for _, p := range phrases {
+		parts := strings.SplitN(p, "=", 2)
+		b.WriteString(`<linking-phrase id="` + parts[0] + `" label="` + parts[1] + `"/>`)
+	}
+
+	b.WriteString(`</linking-phrase-list><connection-list>`)
+
+	for i, c := range connections {
+		parts := strings.SplitN(c, ">", 2)
+		b.WriteString(`<connection id="c` + strconv.Itoa(i) + `" from-id="` + parts[0] + `" to-id="` + parts[1] + `"/>`)
+	}
+
+	b.WriteString(`</connection-list></map></cmap>`)
+
+	return b.String()
+}
+
+func TestLoad(t *testing.T) {
+	src := cxlMap(
+		[]string{"k=Kubernetes", "p=Pods", "n=Nodes"},
+		[]string{"runs=runs"},
+		[]string{"k>runs", "n>runs", "runs>p", "k>n"})
+
+	maps, err := Load(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, p := range maps[0].Propositions {
+		got = append(got, p.String())
+	}
+
+	want := "Kubernetes relates to Nodes, Kubernetes runs Pods, Nodes runs Pods"
+
+	if strings.Join(got, ", ") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ", "), want)
+	}
+}
+
+func TestLoadRejectsConnectionsBetweenLinkingPhrases(t *testing.T) {
+	src := cxlMap(
+		[]string{"k=Kubernetes", "p=Pods"},
+		[]string{"runs=runs", "many=many"},
+		[]string{"k>runs", "runs>many", "many>p"})
+
+	_, err := Load(strings.NewReader(src))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if !strings.Contains(err.Error(), "linking phrase 'runs' to linking phrase 'many'") {
+		t.Errorf("expected the error to name the linking phrases, got %q", err)
+	}
+}
+
+func TestLoadFromFileLoadsCxl(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "Plants.cxl")
+
+	src := cxlMap([]string{"t=Trees", "l=Leaves"}, []string{"have=have"}, []string{"t>have", "have>l"})
+
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	maps, err := conceptmap.LoadFromFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if maps[0].Title != "Plants" {
+		t.Errorf("expected the map to be titled after the file, got %q", maps[0].Title)
+	}
+
+	if len(maps[0].Propositions) != 1 {
+		t.Errorf("expected 1 proposition, got %d", len(maps[0].Propositions))
+	}
+}
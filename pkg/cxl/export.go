@@ -0,0 +1,154 @@
+package cxl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/gosimple/slug"
+)
+
+// Exporter writes a single concept map in CXL format
+var Exporter conceptmap.Exporter = conceptmap.ExporterFunc(Export)
+
+// Layout of the concepts in the exported map. CmapTools needs a position for
+// every concept and linking phrase, so concepts are laid out in a grid, with
+// linking phrases between the concepts they connect, ready to be tidied up
+const (
+	gridColumns   = 5
+	gridSpacingX  = 220
+	gridSpacingY  = 160
+	gridMargin    = 100
+	conceptWidth  = 120
+	conceptHeight = 40
+	phraseWidth   = 90
+	phraseHeight  = 20
+)
+
+type outputDocument struct {
+	XMLName xml.Name `xml:"cmap"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	DCNS    string   `xml:"xmlns:dc,attr"`
+	Meta    struct {
+		Title       string `xml:"dc:title"`
+		Description string `xml:"dc:description,omitempty"`
+	} `xml:"res-meta"`
+	Map struct {
+		Concepts                 []element    `xml:"concept-list>concept"`
+		LinkingPhrases           []element    `xml:"linking-phrase-list>linking-phrase"`
+		Connections              []connection `xml:"connection-list>connection"`
+		ConceptAppearances       []appearance `xml:"concept-appearance-list>concept-appearance"`
+		LinkingPhraseAppearances []appearance `xml:"linking-phrase-appearance-list>linking-phrase-appearance"`
+	} `xml:"map"`
+}
+
+type appearance struct {
+	ID     string `xml:"id,attr"`
+	X      int    `xml:"x,attr"`
+	Y      int    `xml:"y,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+// Export writes maps to w in CXL format. A CXL file holds a single concept map,
+// so maps must contain exactly one. Propositions with the same left concept and
+// predicate share a linking phrase, as they do in diagrams
+func Export(w io.Writer, maps []*conceptmap.ConceptMap) error {
+	if len(maps) != 1 {
+		return fmt.Errorf("a cxl file holds a single concept map, but there are %d", len(maps))
+	}
+
+	m := maps[0]
+
+	doc := new(outputDocument)
+	doc.XMLNS = "http://cmap.ihmc.us/xml/cmap/"
+	doc.DCNS = "http://purl.org/dc/elements/1.1/"
+	doc.Meta.Title = m.Title
+	doc.Meta.Description = m.Description
+
+	positions := map[string]appearance{}
+
+	for _, c := range m.Concepts {
+		// Concepts with the same key are the same concept
+		if _, ok := positions[c.Key()]; ok {
+			continue
+		}
+
+		i := len(positions)
+		id := "concept-" + c.Key()
+
+		doc.Map.Concepts = append(doc.Map.Concepts, element{
+			ID:          id,
+			Label:       c.Label,
+			LongComment: c.Description,
+		})
+
+		a := appearance{
+			ID:     id,
+			X:      gridMargin + (i%gridColumns)*gridSpacingX,
+			Y:      gridMargin + (i/gridColumns)*gridSpacingY,
+			Width:  conceptWidth,
+			Height: conceptHeight,
+		}
+
+		positions[c.Key()] = a
+		doc.Map.ConceptAppearances = append(doc.Map.ConceptAppearances, a)
+	}
+
+	// Linking phrases are numbered in the order they are first used, keyed by the
+	// left concept and predicate that they join. Predicates are compared by their
+	// slugs, as they are when diagrams merge edges
+	phrases := map[[2]string]string{}
+
+	for i, p := range m.Propositions {
+		key := [2]string{p.Left.Key(), slug.Make(string(p.Predicate))}
+		phraseID, ok := phrases[key]
+
+		if !ok {
+			phraseID = fmt.Sprintf("phrase-%d", len(phrases)+1)
+			phrases[key] = phraseID
+
+			doc.Map.LinkingPhrases = append(doc.Map.LinkingPhrases, element{
+				ID:    phraseID,
+				Label: string(p.Predicate),
+			})
+
+			from, to := positions[p.Left.Key()], positions[p.Right.Key()]
+
+			doc.Map.LinkingPhraseAppearances = append(doc.Map.LinkingPhraseAppearances, appearance{
+				ID:     phraseID,
+				X:      (from.X + to.X) / 2,
+				Y:      (from.Y+to.Y)/2 + conceptHeight,
+				Width:  phraseWidth,
+				Height: phraseHeight,
+			})
+
+			doc.Map.Connections = append(doc.Map.Connections, connection{
+				ID:     fmt.Sprintf("connection-%d-from", i),
+				FromID: "concept-" + p.Left.Key(),
+				ToID:   phraseID,
+			})
+		}
+
+		doc.Map.Connections = append(doc.Map.Connections, connection{
+			ID:     fmt.Sprintf("connection-%d-to", i),
+			FromID: phraseID,
+			ToID:   "concept-" + p.Right.Key(),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,92 @@
+package cxl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+func exportMap(t *testing.T, propositions string) (*conceptmap.ConceptMap, string) {
+	t.Helper()
+
+	maps, err := conceptmap.LoadFromYamlReader(strings.NewReader("title: Test\npropositions: |\n" + propositions))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+
+	if err := Export(&b, maps); err != nil {
+		t.Fatal(err)
+	}
+
+	return maps[0], b.String()
+}
+
+func TestExportKeepsPhrasesWithSimilarSlugsApart(t *testing.T) {
+	_, out := exportMap(t, "  A B | runs | C\n  A | b runs | D\n")
+
+	if n := strings.Count(out, "<linking-phrase "); n != 2 {
+		t.Errorf("expected 2 linking phrases, got %d in\n%s", n, out)
+	}
+
+	for _, id := range []string{`id="phrase-1"`, `id="phrase-2"`} {
+		if !strings.Contains(out, "<linking-phrase "+id) {
+			t.Errorf("expected a linking phrase with %s in\n%s", id, out)
+		}
+	}
+}
+
+func TestExportSharesPhrasesBetweenPropositions(t *testing.T) {
+	tests := []struct {
+		name         string
+		propositions string
+		want         int
+	}{
+		{
+			name:         "same predicate",
+			propositions: "  Kubernetes runs Pods\n  Kubernetes runs Jobs\n  Nodes runs Pods\n",
+			want:         2,
+		},
+		{
+			name:         "predicates with the same slug",
+			propositions: "  Kubernetes | Runs | Pods\n  Kubernetes runs Jobs\n",
+			want:         1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, out := exportMap(t, tt.propositions)
+
+			if n := strings.Count(out, "<linking-phrase "); n != tt.want {
+				t.Errorf("expected %d linking phrases, got %d in\n%s", tt.want, n, out)
+			}
+		})
+	}
+}
+
+func TestExportRoundTrips(t *testing.T) {
+	m, out := exportMap(t, "  A B | runs | C\n  A | b runs | D\n  A | b runs | C\n  C is a A\n")
+
+	maps, err := Load(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, p := range maps[0].Propositions {
+		got = append(got, p.String())
+	}
+
+	want := []string{}
+	for _, p := range m.Propositions {
+		want = append(want, p.String())
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,210 @@
+// Package cypher exports concept maps as cypher statements, so that they can be
+// bulk imported into a graph database such as Neo4j.
+//
+// Every statement is a MERGE, so importing the same maps more than once leaves
+// the database unchanged. Concepts are merged on their key, so concepts with the
+// same key in different maps are the same node
+package cypher
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// Labels and relationship types used in exported graphs
+const (
+	ConceptLabel      = "Concept"
+	ConceptMapLabel   = "ConceptMap"
+	ContainsType      = "CONTAINS"
+	RelatesToType     = "RELATES_TO"
+	PredicateProperty = "predicate"
+)
+
+// Exporter writes concept maps as cypher statements
+type Exporter struct {
+	predicatesAsProperties bool
+}
+
+type ExporterOption func(*Exporter)
+
+// WithPredicatesAsProperties exports every proposition as a RELATES_TO
+// relationship with the predicate in its predicate property, rather than using
+// the predicate as the relationship type. This keeps the predicate exactly as it
+// is written, at the expense of less natural queries
+func WithPredicatesAsProperties() ExporterOption {
+	return func(e *Exporter) {
+		e.predicatesAsProperties = true
+	}
+}
+
+func NewExporter(opts ...ExporterOption) *Exporter {
+	e := &Exporter{}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Export writes the statements for maps to w, each terminated by a semicolon
+func (e *Exporter) Export(w io.Writer, maps []*conceptmap.ConceptMap) error {
+	for _, s := range e.Statements(maps) {
+		if _, err := fmt.Fprintf(w, "%s;\n", s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Statements returns the cypher statements that create maps in a graph database
+func (e *Exporter) Statements(maps []*conceptmap.ConceptMap) []string {
+	output := []string{
+		fmt.Sprintf("CREATE CONSTRAINT concept_key IF NOT EXISTS FOR (c:%s) REQUIRE c.key IS UNIQUE", ConceptLabel),
+		fmt.Sprintf("CREATE CONSTRAINT concept_map_key IF NOT EXISTS FOR (m:%s) REQUIRE m.key IS UNIQUE", ConceptMapLabel),
+	}
+
+	for _, m := range maps {
+		output = append(output, mergeNode("m", ConceptMapLabel, m.Slug(), []property{
+			{"title", m.Title},
+			{"description", strings.TrimSpace(m.Description)},
+		}))
+
+		for _, c := range m.Concepts {
+			output = append(output,
+				mergeNode("c", ConceptLabel, c.Key(), []property{
+					{"label", c.Label},
+					{"description", strings.TrimSpace(c.Description)},
+					{"isKeyConcept", c.IsKeyConcept},
+					{"aliases", c.Aliases},
+					{"tags", c.Tags},
+					{"categories", c.Categories},
+					{"group", c.Group},
+				}),
+				mergeRelationship(ConceptMapLabel, m.Slug(), ContainsType, nil, ConceptLabel, c.Key()))
+		}
+
+		for _, p := range m.Propositions {
+			// Predicates without a slug, such as those written only in punctuation,
+			// have no relationship type, so keep their predicate as a property
+			if relType := RelationshipType(p.Predicate); relType != "" && !e.predicatesAsProperties {
+				output = append(output, mergeRelationship(ConceptLabel, p.Left.Key(), relType, nil, ConceptLabel, p.Right.Key()))
+			} else {
+				output = append(output, mergeRelationship(ConceptLabel, p.Left.Key(), RelatesToType, []property{{PredicateProperty, string(p.Predicate)}}, ConceptLabel, p.Right.Key()))
+			}
+		}
+	}
+
+	return output
+}
+
+// RelationshipType returns the relationship type that p is exported as, which is
+// its slug in upper snake case, such as IS_A. It returns an empty string if p has
+// no slug, in which case p is exported as a RELATES_TO relationship
+func RelationshipType(p conceptmap.Predicate) string {
+	return strings.ToUpper(strings.ReplaceAll(p.Slug(), "-", "_"))
+}
+
+type property struct {
+	name  string
+	value interface{}
+}
+
+// mergeNode merges the node with label and key, then sets any of properties that
+// have values, leaving existing values in place for those that don't
+func mergeNode(variable string, label string, key string, properties []property) string {
+	s := fmt.Sprintf("MERGE (%s:%s {key: %s})", variable, identifier(label), literal(key))
+
+	if set := mapLiteral(properties); set != "" {
+		s += fmt.Sprintf(" SET %s += %s", variable, set)
+	}
+
+	return s
+}
+
+func mergeRelationship(fromLabel string, fromKey string, relType string, properties []property, toLabel string, toKey string) string {
+	rel := ":" + identifier(relType)
+
+	if props := mapLiteral(properties); props != "" {
+		rel += " " + props
+	}
+
+	return fmt.Sprintf("MATCH (a:%s {key: %s}), (b:%s {key: %s}) MERGE (a)-[%s]->(b)",
+		identifier(fromLabel), literal(fromKey), identifier(toLabel), literal(toKey), rel)
+}
+
+// mapLiteral returns properties with values as a cypher map, or an empty string
+// if none of them have values
+func mapLiteral(properties []property) string {
+	entries := []string{}
+
+	for _, p := range properties {
+		switch v := p.value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+		case bool:
+			if !v {
+				continue
+			}
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		}
+
+		entries = append(entries, fmt.Sprintf("%s: %s", identifier(p.name), literal(p.value)))
+	}
+
+	if len(entries) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+var stringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`)
+
+func literal(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return `"` + stringEscaper.Replace(v) + `"`
+	case bool:
+		return fmt.Sprint(v)
+	case []string:
+		items := make([]string, len(v))
+		for i, s := range v {
+			items[i] = literal(s)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	return literal(fmt.Sprint(v))
+}
+
+// identifier quotes s with backticks if it isn't a valid plain identifier
+func identifier(s string) string {
+	for i, r := range s {
+		if r == '_' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+
+		return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+	}
+
+	if s == "" {
+		return "``"
+	}
+
+	return s
+}
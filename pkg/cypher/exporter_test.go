@@ -0,0 +1,141 @@
+package cypher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+func loadMaps(t *testing.T, src string) []*conceptmap.ConceptMap {
+	t.Helper()
+
+	maps, err := conceptmap.LoadFromYamlReader(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return maps
+}
+
+func TestStatementsAreIdempotent(t *testing.T) {
+	maps := loadMaps(t, "title: Kubernetes\npropositions: |\n  Kubernetes runs Pods\n  Pods contain Containers\n")
+
+	for _, s := range NewExporter().Statements(maps) {
+		switch {
+		case strings.HasPrefix(s, "CREATE CONSTRAINT"):
+			if !strings.Contains(s, "IF NOT EXISTS") {
+				t.Errorf("expected constraint to be created only if it doesn't exist: %s", s)
+			}
+		case strings.Contains(s, "CREATE"):
+			t.Errorf("expected only MERGE statements: %s", s)
+		case !strings.Contains(s, "MERGE"):
+			t.Errorf("expected a MERGE statement: %s", s)
+		}
+	}
+}
+
+func TestStatements(t *testing.T) {
+	maps := loadMaps(t, `title: Kubernetes
+propositions: |
+  Kubernetes runs Pods
+  Pods | !!! | Containers
+concepts:
+  Kubernetes:
+    description: "Says \"hi\"\nand C:\\ bye"
+    isKeyConcept: true
+    tags: [Orchestration]
+`)
+
+	tests := []struct {
+		name string
+		opts []ExporterOption
+		want []string
+	}{
+		{
+			name: "predicates as relationship types",
+			want: []string{
+				`MERGE (m:ConceptMap {key: "kubernetes"}) SET m += {title: "Kubernetes"}`,
+				`MERGE (c:Concept {key: "kubernetes"}) SET c += {label: "Kubernetes", description: "Says \"hi\"\nand C:\\ bye", isKeyConcept: true, tags: ["Orchestration"]}`,
+				`MATCH (a:ConceptMap {key: "kubernetes"}), (b:Concept {key: "kubernetes"}) MERGE (a)-[:CONTAINS]->(b)`,
+				`MERGE (c:Concept {key: "pods"}) SET c += {label: "Pods"}`,
+				`MATCH (a:Concept {key: "kubernetes"}), (b:Concept {key: "pods"}) MERGE (a)-[:RUNS]->(b)`,
+				`MATCH (a:Concept {key: "pods"}), (b:Concept {key: "containers"}) MERGE (a)-[:RELATES_TO {predicate: "!!!"}]->(b)`,
+			},
+		},
+		{
+			name: "predicates as properties",
+			opts: []ExporterOption{WithPredicatesAsProperties()},
+			want: []string{
+				`MATCH (a:Concept {key: "kubernetes"}), (b:Concept {key: "pods"}) MERGE (a)-[:RELATES_TO {predicate: "runs"}]->(b)`,
+				`MATCH (a:Concept {key: "pods"}), (b:Concept {key: "containers"}) MERGE (a)-[:RELATES_TO {predicate: "!!!"}]->(b)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements := NewExporter(tt.opts...).Statements(maps)
+			all := strings.Join(statements, "\n")
+
+			for _, want := range tt.want {
+				found := false
+
+				for _, s := range statements {
+					if s == want {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					t.Errorf("expected statement\n%s\nin\n%s", want, all)
+				}
+			}
+
+			if strings.Contains(all, "``") {
+				t.Errorf("expected no empty identifiers in\n%s", all)
+			}
+		})
+	}
+}
+
+func TestRelationshipType(t *testing.T) {
+	tests := []struct {
+		predicate conceptmap.Predicate
+		want      string
+	}{
+		{"runs", "RUNS"},
+		{"is a", "IS_A"},
+		{"Is Built From", "IS_BUILT_FROM"},
+		{"runs-on", "RUNS_ON"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		if got := RelationshipType(tt.predicate); got != tt.want {
+			t.Errorf("RelationshipType(%q) = %q, want %q", tt.predicate, got, tt.want)
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"Concept", "Concept"},
+		{"IS_A", "IS_A"},
+		{"_private", "_private"},
+		{"v2", "v2"},
+		{"2v", "`2v`"},
+		{"has space", "`has space`"},
+		{"back`tick", "`back``tick`"},
+	}
+
+	for _, tt := range tests {
+		if got := identifier(tt.s); got != tt.want {
+			t.Errorf("identifier(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
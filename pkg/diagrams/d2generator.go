@@ -24,11 +24,16 @@ type D2DiagramGenerator struct {
 	ruler          *textmeasure.Ruler
 	rulerFactory   D2RulerFactory
 	graphModifiers []D2GraphModifier
+	detailGrouper  ConceptGrouper
+	summaryGrouper ConceptGrouper
+	radius         int
+	maxNodes       int
 }
 
 func NewD2DiagramGenerator(opts ...D2DiagramGeneratorOption) *D2DiagramGenerator {
 	d := &D2DiagramGenerator{
 		direction:      DirectionDown,
+		radius:         1,
 		rulerFactory:   defaultRulerFactory,
 		graphModifiers: []D2GraphModifier{},
 	}
@@ -41,6 +46,28 @@ func NewD2DiagramGenerator(opts ...D2DiagramGeneratorOption) *D2DiagramGenerator
 }
 
 func (d *D2DiagramGenerator) D2Script(ctx context.Context, propositions []*conceptmap.Proposition, modifiers ...D2GraphModifier) (string, error) {
+	return d.GroupedD2Script(ctx, propositions, nil, modifiers...)
+}
+
+// CollapsedD2Script is like D2Script, but draws each group of concepts in groups,
+// which maps concept keys to group names, as a single node. Every group, and
+// every one of concepts that isn't in a group, is drawn, even if all of its
+// propositions are within a group
+func (d *D2DiagramGenerator) CollapsedD2Script(ctx context.Context, concepts []*conceptmap.Concept, propositions []*conceptmap.Proposition, groups map[string]string, modifiers ...D2GraphModifier) (string, error) {
+	nodes, collapsed := collapseGroups(concepts, propositions, groups)
+	return d.d2Script(ctx, nodes, collapsed, nil, modifiers...)
+}
+
+// GroupedD2Script is like D2Script, but draws each group of concepts in groups,
+// which maps concept keys to group names, inside a D2 container. Predicates are
+// drawn in the same container as their left concept
+func (d *D2DiagramGenerator) GroupedD2Script(ctx context.Context, propositions []*conceptmap.Proposition, groups map[string]string, modifiers ...D2GraphModifier) (string, error) {
+	return d.d2Script(ctx, nil, propositions, groups, modifiers...)
+}
+
+// d2Script draws propositions, as GroupedD2Script does, along with any of
+// concepts that aren't in a proposition
+func (d *D2DiagramGenerator) d2Script(ctx context.Context, concepts []*conceptmap.Concept, propositions []*conceptmap.Proposition, groups map[string]string, modifiers ...D2GraphModifier) (string, error) {
 	var err error
 
 	script := `direction: %s
@@ -61,6 +88,12 @@ classes: {
 			italic: true
 		}
 	}
+	group: {
+		style: {
+			border-radius: 8
+			stroke-dash: 3
+		}
+	}
 }`
 
 	_, graph, err := d2lib.Compile(ctx, fmt.Sprintf(script, d.direction), nil)
@@ -76,6 +109,15 @@ classes: {
 
 	appendConcept := d.distinctConceptAppenderFunc()
 
+	// conceptPath returns the key of the concept, prefixed with the key of its
+	// group's container, if it has one
+	conceptPath := func(c *conceptmap.Concept) string {
+		if g := groups[c.Key()]; g != "" {
+			return groupKey(g) + "." + c.Key()
+		}
+		return c.Key()
+	}
+
 	for _, mod := range modifiers {
 		graph, err = mod(graph)
 		if err != nil {
@@ -86,7 +128,14 @@ classes: {
 	for _, proposition := range propositions {
 
 		predicate := (string)(proposition.Predicate)
-		predicateKey := slug.Make(strings.Join([]string{proposition.Left.Key(), predicate}, " "))
+		predicateKey := predicateKey(proposition)
+		leftPath := conceptPath(proposition.Left)
+		rightPath := conceptPath(proposition.Right)
+
+		if g := groups[proposition.Left.Key()]; g != "" {
+			predicateKey = groupKey(g) + "." + predicateKey
+		}
+
 		leftClass := defaultConceptClass
 		rightClass := defaultConceptClass
 
@@ -96,19 +145,19 @@ classes: {
 		}
 
 		// Left concept
-		graph, err = appendConcept(graph, proposition.Left, leftClass)
+		graph, err = appendConcept(graph, leftPath, proposition.Left, leftClass)
 		if err != nil {
 			return "", err
 		}
 
 		// Right concept
-		graph, err = appendConcept(graph, proposition.Right, rightClass)
+		graph, err = appendConcept(graph, rightPath, proposition.Right, rightClass)
 		if err != nil {
 			return "", err
 		}
 
 		// Predicate -> Right Concept
-		graph, _, err = d2oracle.Create(graph, fmt.Sprintf("%s -> %s", predicateKey, proposition.Right.Key()))
+		graph, _, err = d2oracle.Create(graph, fmt.Sprintf("%s -> %s", predicateKey, rightPath))
 		if err != nil {
 			return "", err
 		}
@@ -130,7 +179,7 @@ classes: {
 			}
 
 			// Left Concept -> Predicate
-			graph, _, err = d2oracle.Create(graph, fmt.Sprintf("%s -> %s", proposition.Left.Key(), predicateKey))
+			graph, _, err = d2oracle.Create(graph, fmt.Sprintf("%s -> %s", leftPath, predicateKey))
 			if err != nil {
 				return "", err
 			}
@@ -140,12 +189,35 @@ classes: {
 
 	}
 
+	// Concepts that aren't in any proposition
+	for _, c := range concepts {
+		graph, err = appendConcept(graph, conceptPath(c), c, defaultConceptClass)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Group labels must go last, after their containers' contents
+	graph, err = appendGroups(graph, propositions, groups)
+	if err != nil {
+		return "", err
+	}
+
 	return d2format.Format(graph.AST), nil
 }
 
 func (d *D2DiagramGenerator) GenerateConceptMapSummarySVG(ctx context.Context, cmap *conceptmap.ConceptMap, file string) error {
 	propositions := cmap.Propositions
 
+	if d.summaryGrouper != nil {
+		script, err := d.CollapsedD2Script(ctx, cmap.Concepts, propositions, d.summaryGrouper(cmap))
+		if err != nil {
+			return err
+		}
+
+		return d.generateSVGFileFromScript(ctx, script, file)
+	}
+
 	if cmap.HasKeyConcepts() {
 		propositions = cmap.Propositions.ConnectingConcepts(cmap.KeyConcepts()...)
 	}
@@ -159,7 +231,13 @@ func (d *D2DiagramGenerator) GenerateConceptMapSummarySVG(ctx context.Context, c
 }
 
 func (d *D2DiagramGenerator) GenerateConceptMapDetailSVG(ctx context.Context, cmap *conceptmap.ConceptMap, file string) error {
-	script, err := d.D2Script(ctx, cmap.Propositions)
+	var groups map[string]string
+
+	if d.detailGrouper != nil {
+		groups = d.detailGrouper(cmap)
+	}
+
+	script, err := d.GroupedD2Script(ctx, cmap.Propositions, groups)
 	if err != nil {
 		return err
 	}
@@ -167,10 +245,29 @@ func (d *D2DiagramGenerator) GenerateConceptMapDetailSVG(ctx context.Context, cm
 	return d.generateSVGFileFromScript(ctx, script, file)
 }
 
+// GenerateSingleConceptSVG draws the neighbourhood of concept, out to the
+// generator's radius, fading concepts the further they are from it
 func (d *D2DiagramGenerator) GenerateSingleConceptSVG(ctx context.Context, cmap *conceptmap.ConceptMap, concept *conceptmap.Concept, file string) error {
-	filtered := cmap.Propositions.InvolvingConcepts(concept)
+	filtered, distances := cmap.Propositions.Neighbourhood(concept, d.radius, d.maxNodes)
+
+	script, err := d.D2Script(ctx, filtered, emphasiseConceptWithKey(concept.Key()), fadeByDistance(filtered, distances))
+	if err != nil {
+		return err
+	}
+
+	return d.generateSVGFileFromScript(ctx, script, file)
+}
+
+// GenerateConceptMapPartSVG draws the propositions involving concepts, which are
+// one part of a concept map that is too large to draw in a single diagram
+func (d *D2DiagramGenerator) GenerateConceptMapPartSVG(ctx context.Context, cmap *conceptmap.ConceptMap, concepts []*conceptmap.Concept, file string) error {
+	modifiers := []D2GraphModifier{}
+
+	for _, c := range concepts {
+		modifiers = append(modifiers, emphasiseConceptWithKey(c.Key()))
+	}
 
-	script, err := d.D2Script(ctx, filtered, emphasiseConceptWithKey(concept.Key()))
+	script, err := d.D2Script(ctx, cmap.Propositions.InvolvingConcepts(concepts...), modifiers...)
 	if err != nil {
 		return err
 	}
@@ -178,27 +275,103 @@ func (d *D2DiagramGenerator) GenerateSingleConceptSVG(ctx context.Context, cmap
 	return d.generateSVGFileFromScript(ctx, script, file)
 }
 
+// GenerateConceptMapOverviewSVG draws the whole concept map with each group in
+// groups, which maps concept keys to group names, collapsed into a single node.
+// Group nodes are linked to the urls in links, which is keyed by group name
+func (d *D2DiagramGenerator) GenerateConceptMapOverviewSVG(ctx context.Context, cmap *conceptmap.ConceptMap, groups map[string]string, links map[string]string, file string) error {
+	groupLinks := map[string]string{}
+
+	for name, link := range links {
+		groupLinks[slug.Make(name)] = link
+	}
+
+	script, err := d.CollapsedD2Script(ctx, cmap.Concepts, cmap.Propositions, groups, addLinksToConcepts(groupLinks))
+	if err != nil {
+		return err
+	}
+
+	return d.generateSVGFileFromScript(ctx, script, file)
+}
+
+// GenerateTagSVG draws all propositions involving concepts tagged with tag,
+// emphasising the tagged concepts
+func (d *D2DiagramGenerator) GenerateTagSVG(ctx context.Context, cmap *conceptmap.ConceptMap, tag string, file string) error {
+	modifiers := []D2GraphModifier{}
+
+	for _, c := range cmap.ConceptsWithTag(tag) {
+		modifiers = append(modifiers, emphasiseConceptWithKey(c.Key()))
+	}
+
+	script, err := d.D2Script(ctx, cmap.Propositions.InvolvingTag(tag), modifiers...)
+	if err != nil {
+		return err
+	}
+
+	return d.generateSVGFileFromScript(ctx, script, file)
+}
+
+// GeneratePathSVG draws the chains of propositions in paths, which connect from
+// to to, emphasising the concepts at either end
+func (d *D2DiagramGenerator) GeneratePathSVG(ctx context.Context, cmap *conceptmap.ConceptMap, from *conceptmap.Concept, to *conceptmap.Concept, paths []conceptmap.PropositionList, file string) error {
+	propositions := conceptmap.PropositionList{}
+	seen := map[*conceptmap.Proposition]bool{}
+
+	for _, path := range paths {
+		for _, p := range path {
+			if !seen[p] {
+				seen[p] = true
+				propositions = append(propositions, p)
+			}
+		}
+	}
+
+	script, err := d.D2Script(ctx, propositions, emphasiseConceptWithKey(from.Key()), emphasiseConceptWithKey(to.Key()))
+	if err != nil {
+		return err
+	}
+
+	return d.generateSVGFileFromScript(ctx, script, file)
+}
+
+// GeneratePropositionsSVG draws propositions, which may come from any number of
+// concept maps
+func (d *D2DiagramGenerator) GeneratePropositionsSVG(ctx context.Context, propositions []*conceptmap.Proposition, file string) error {
+	script, err := d.D2Script(ctx, propositions)
+	if err != nil {
+		return err
+	}
+
+	return d.generateSVGFileFromScript(ctx, script, file)
+}
+
+// predicateKey is the key of the node drawn for the predicate of p. Propositions
+// with the same left concept and predicate share a node
+func predicateKey(p *conceptmap.Proposition) string {
+	return slug.Make(strings.Join([]string{p.Left.Key(), string(p.Predicate)}, " "))
+}
+
 // distinctConceptAppenderFunc returns a function that appends a concept to a graph
 // exactly once. Calling the function multiple times with the same concept will only
-// append the concept once. Concepts with the same Key are considered equivalent
-func (d *D2DiagramGenerator) distinctConceptAppenderFunc() func(*d2graph.Graph, *conceptmap.Concept, string) (*d2graph.Graph, error) {
+// append the concept once. Concepts with the same Key are considered equivalent.
+// The concept is drawn at path, which is its key, possibly nested in a container
+func (d *D2DiagramGenerator) distinctConceptAppenderFunc() func(*d2graph.Graph, string, *conceptmap.Concept, string) (*d2graph.Graph, error) {
 
 	// Keeps track of which concepts we've added to the diagram, so that we can avoid
 	// adding them more than once
 	appendedConcepts := map[string]int{}
 
-	return func(graph *d2graph.Graph, concept *conceptmap.Concept, class string) (*d2graph.Graph, error) {
+	return func(graph *d2graph.Graph, path string, concept *conceptmap.Concept, class string) (*d2graph.Graph, error) {
 		_, alreadyAdded := appendedConcepts[concept.Key()]
 
 		if !alreadyAdded {
 			var err error
 
-			graph, err = d2oracle.Set(graph, fmt.Sprintf("%s.class", concept.Key()), nil, &class)
+			graph, err = d2oracle.Set(graph, fmt.Sprintf("%s.class", path), nil, &class)
 			if err != nil {
 				return graph, err
 			}
 
-			graph, err = d2oracle.Set(graph, fmt.Sprintf("%s.label", concept.Key()), nil, &concept.Label)
+			graph, err = d2oracle.Set(graph, fmt.Sprintf("%s.label", path), nil, &concept.Label)
 			if err != nil {
 				return graph, err
 			}
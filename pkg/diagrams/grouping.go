@@ -0,0 +1,255 @@
+package diagrams
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/gosimple/slug"
+	"oss.terrastruct.com/d2/d2graph"
+	"oss.terrastruct.com/d2/d2oracle"
+)
+
+// ConceptGrouper assigns the concepts of a concept map to named groups, returning
+// a map of concept key to group name. Each group is drawn as a D2 container.
+// Concepts without a group are drawn outside of any container
+type ConceptGrouper func(cmap *conceptmap.ConceptMap) map[string]string
+
+// GroupByTags groups each concept by the first of its tags that is one of tags. If
+// no tags are given, concepts are grouped by their first tag
+func GroupByTags(tags ...string) ConceptGrouper {
+	return func(cmap *conceptmap.ConceptMap) map[string]string {
+		groups := map[string]string{}
+
+		for _, c := range cmap.Concepts {
+			for _, t := range c.Tags {
+				if len(tags) == 0 || containsTag(tags, t) {
+					groups[c.Key()] = t
+					break
+				}
+			}
+		}
+
+		return groups
+	}
+}
+
+// GroupByField groups each concept by its Group field
+func GroupByField() ConceptGrouper {
+	return func(cmap *conceptmap.ConceptMap) map[string]string {
+		groups := map[string]string{}
+
+		for _, c := range cmap.Concepts {
+			if c.Group != "" {
+				groups[c.Key()] = c.Group
+			}
+		}
+
+		return groups
+	}
+}
+
+// GroupByConnectedComponent groups concepts that are connected to each other by
+// propositions. Each group is named after its most connected concept. Nothing is
+// grouped if every concept is connected
+func GroupByConnectedComponent() ConceptGrouper {
+	return func(cmap *conceptmap.ConceptMap) map[string]string {
+		groups := map[string]string{}
+		components := cmap.Propositions.ConnectedComponents()
+
+		if len(components) < 2 {
+			return groups
+		}
+
+		for _, component := range components {
+			name := mostConnectedConcept(cmap.Propositions, component).Label
+
+			for _, c := range component {
+				groups[c.Key()] = name
+			}
+		}
+
+		return groups
+	}
+}
+
+// GroupByCommunity groups concepts into densely connected communities. Each group
+// is named after its most connected concept
+func GroupByCommunity() ConceptGrouper {
+	return func(cmap *conceptmap.ConceptMap) map[string]string {
+		groups := map[string]string{}
+
+		for _, community := range cmap.Propositions.Communities() {
+			name := mostConnectedConcept(cmap.Propositions, community).Label
+
+			for _, c := range community {
+				groups[c.Key()] = name
+			}
+		}
+
+		return groups
+	}
+}
+
+// GroupByKeyConcept groups each concept with the key concept nearest to it. Each
+// group is named after its key concept. Concepts that aren't connected to any
+// key concept are grouped as Other. Nothing is grouped if the map has no key
+// concepts
+func GroupByKeyConcept() ConceptGrouper {
+	return func(cmap *conceptmap.ConceptMap) map[string]string {
+		groups := map[string]string{}
+		nearest := map[string]int{}
+
+		for _, kc := range cmap.KeyConcepts() {
+			_, distances := cmap.Propositions.Neighbourhood(kc, len(cmap.Concepts), 0)
+
+			for key, d := range distances {
+				if n, ok := nearest[key]; !ok || d < n {
+					nearest[key] = d
+					groups[key] = kc.Label
+				}
+			}
+		}
+
+		if len(groups) == 0 {
+			return groups
+		}
+
+		for _, c := range cmap.Concepts {
+			if _, ok := groups[c.Key()]; !ok {
+				groups[c.Key()] = "Other"
+			}
+		}
+
+		return groups
+	}
+}
+
+// mostConnectedConcept returns the concept in concepts that is involved in the
+// most propositions
+func mostConnectedConcept(propositions conceptmap.PropositionList, concepts []*conceptmap.Concept) *conceptmap.Concept {
+	var best *conceptmap.Concept
+	bestDegree := -1
+
+	for _, c := range concepts {
+		if degree := len(propositions.InvolvingConcepts(c)); degree > bestDegree {
+			best = c
+			bestDegree = degree
+		}
+	}
+
+	return best
+}
+
+// collapseGroups replaces every concept that belongs to a group with a single
+// concept representing the group. Propositions between concepts in the same group
+// are dropped, and identical propositions between groups are merged. The nodes
+// returned hold a concept for every group and every ungrouped concept, in the
+// order they first appear in concepts
+func collapseGroups(concepts []*conceptmap.Concept, propositions []*conceptmap.Proposition, groups map[string]string) ([]*conceptmap.Concept, []*conceptmap.Proposition) {
+	nodes := []*conceptmap.Concept{}
+	output := []*conceptmap.Proposition{}
+	groupConcepts := map[string]*conceptmap.Concept{}
+	seen := map[string]bool{}
+
+	collapse := func(c *conceptmap.Concept) *conceptmap.Concept {
+		g := groups[c.Key()]
+		if g == "" {
+			return c
+		}
+
+		gc, ok := groupConcepts[g]
+		if !ok {
+			gc = &conceptmap.Concept{Label: g}
+			groupConcepts[g] = gc
+		}
+
+		return gc
+	}
+
+	drawn := map[string]bool{}
+
+	for _, c := range concepts {
+		node := collapse(c)
+
+		if !drawn[node.Key()] {
+			drawn[node.Key()] = true
+			nodes = append(nodes, node)
+		}
+	}
+
+	for _, p := range propositions {
+		left := collapse(p.Left)
+		right := collapse(p.Right)
+
+		if left.Key() == right.Key() {
+			continue
+		}
+
+		collapsed := &conceptmap.Proposition{
+			Left:      left,
+			Right:     right,
+			Predicate: p.Predicate,
+		}
+
+		if !seen[collapsed.String()] {
+			seen[collapsed.String()] = true
+			output = append(output, collapsed)
+		}
+	}
+
+	return nodes, output
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if slug.Make(t) == slug.Make(tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupKey is the key of the D2 container for group. slug.Make trims leading
+// underscores, so the prefix can't clash with the key of a concept or predicate
+func groupKey(group string) string {
+	return "_group-" + slug.Make(group)
+}
+
+// appendGroups adds a labelled container for each group that a concept in
+// propositions belongs to
+func appendGroups(graph *d2graph.Graph, propositions []*conceptmap.Proposition, groups map[string]string) (*d2graph.Graph, error) {
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, p := range propositions {
+		for _, c := range []*conceptmap.Concept{p.Left, p.Right} {
+			if g := groups[c.Key()]; g != "" && !seen[groupKey(g)] {
+				seen[groupKey(g)] = true
+				names = append(names, g)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	class := "group"
+
+	for _, name := range names {
+		var err error
+		label := name
+
+		graph, err = d2oracle.Set(graph, fmt.Sprintf("%s.class", groupKey(name)), nil, &class)
+		if err != nil {
+			return graph, err
+		}
+
+		graph, err = d2oracle.Set(graph, fmt.Sprintf("%s.label", groupKey(name)), nil, &label)
+		if err != nil {
+			return graph, err
+		}
+	}
+
+	return graph, nil
+}
@@ -0,0 +1,56 @@
+package diagrams
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+func TestGroupKeyDoesNotClashWithConceptKeys(t *testing.T) {
+	c := &conceptmap.Concept{Label: "Group X"}
+
+	if groupKey("X") == c.Key() {
+		t.Errorf("group key %q clashes with the key of concept %q", groupKey("X"), c.Label)
+	}
+}
+
+func TestCollapsedD2ScriptDrawsEveryGroup(t *testing.T) {
+	a := &conceptmap.Concept{Label: "A"}
+	b := &conceptmap.Concept{Label: "B"}
+	c := &conceptmap.Concept{Label: "C"}
+	d := &conceptmap.Concept{Label: "D"}
+	e := &conceptmap.Concept{Label: "E"}
+
+	cmap := &conceptmap.ConceptMap{
+		Concepts: []*conceptmap.Concept{a, b, c, d, e},
+		Propositions: conceptmap.PropositionList{
+			{Left: a, Predicate: "runs", Right: b},
+			{Left: c, Predicate: "runs", Right: d},
+		},
+	}
+
+	groups := GroupByConnectedComponent()(cmap)
+
+	nodes, propositions := collapseGroups(cmap.Concepts, cmap.Propositions, groups)
+
+	if len(propositions) != 0 {
+		t.Errorf("expected propositions within groups to be dropped, got %d", len(propositions))
+	}
+
+	if len(nodes) != 3 {
+		t.Fatalf("expected a node for each of the 3 components, got %d", len(nodes))
+	}
+
+	script, err := NewD2DiagramGenerator().CollapsedD2Script(context.Background(), cmap.Concepts, cmap.Propositions, groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range nodes {
+		if !strings.Contains(script, n.Key()+".label: "+n.Label) {
+			t.Errorf("expected a node for %q in\n%s", n.Label, script)
+		}
+	}
+}
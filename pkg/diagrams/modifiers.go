@@ -2,6 +2,8 @@ package diagrams
 
 import (
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
 	"oss.terrastruct.com/d2/d2graph"
@@ -17,13 +19,66 @@ func emphasiseConceptWithKey(key string) D2GraphModifier {
 	}
 }
 
-func addLinksToConcepts(cmap *conceptmap.ConceptMap, concepts []*conceptmap.Concept) D2GraphModifier {
+// fadeByDistance reduces the opacity of concepts and predicates more than one hop
+// away, according to distances, which is keyed by concept key
+func fadeByDistance(propositions []*conceptmap.Proposition, distances map[string]int) D2GraphModifier {
 	return func(g *d2graph.Graph) (*d2graph.Graph, error) {
-		for _, concept := range concepts {
-			// link := fmt.Sprintf("http://localhost:8080/%s_%s/", cmap.Slug(), concept.Key())
-			link := "https://google.com"
+		var err error
 
-			g, err := d2oracle.Set(g, fmt.Sprintf("%s.link", concept.Key()), nil, &link)
+		faded := map[string]bool{}
+
+		fade := func(key string, distance int) error {
+			if distance < 2 || faded[key] {
+				return nil
+			}
+
+			faded[key] = true
+			opacity := fmt.Sprintf("%.1f", math.Max(0.2, 1-0.3*float64(distance-1)))
+
+			g, err = d2oracle.Set(g, fmt.Sprintf("%s.style.opacity", key), nil, &opacity)
+			return err
+		}
+
+		for _, p := range propositions {
+			l := distances[p.Left.Key()]
+			r := distances[p.Right.Key()]
+
+			if err := fade(p.Left.Key(), l); err != nil {
+				return g, err
+			}
+
+			if err := fade(p.Right.Key(), r); err != nil {
+				return g, err
+			}
+
+			if l > r {
+				r = l
+			}
+
+			if err := fade(predicateKey(p), r); err != nil {
+				return g, err
+			}
+		}
+
+		return g, nil
+	}
+}
+
+// addLinksToConcepts links each concept in links, which maps concept keys to urls
+func addLinksToConcepts(links map[string]string) D2GraphModifier {
+	return func(g *d2graph.Graph) (*d2graph.Graph, error) {
+		keys := make([]string, 0, len(links))
+		for key := range links {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var err error
+			link := links[key]
+
+			g, err = d2oracle.Set(g, fmt.Sprintf("%s.link", key), nil, &link)
 			if err != nil {
 				return g, err
 			}
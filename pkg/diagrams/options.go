@@ -19,3 +19,36 @@ func WithDirection(direction Direction) D2DiagramGeneratorOption {
 		d.direction = direction
 	}
 }
+
+func WithDetailGrouper(g ConceptGrouper) D2DiagramGeneratorOption {
+	return func(d *D2DiagramGenerator) {
+		d.detailGrouper = g
+	}
+}
+
+// WithSummaryGrouper draws summary diagrams as an overview of the whole map, with
+// each group of concepts collapsed into a single node
+func WithSummaryGrouper(g ConceptGrouper) D2DiagramGeneratorOption {
+	return func(d *D2DiagramGenerator) {
+		d.summaryGrouper = g
+	}
+}
+
+// WithNeighbourhoodRadius sets how many hops from a concept its diagram extends.
+// Defaults to 1, which shows only the concept's own propositions
+func WithNeighbourhoodRadius(radius int) D2DiagramGeneratorOption {
+	return func(d *D2DiagramGenerator) {
+		if radius < 1 {
+			radius = 1
+		}
+		d.radius = radius
+	}
+}
+
+// WithMaxNodes limits the number of concepts drawn in a concept's diagram. Zero,
+// the default, means no limit
+func WithMaxNodes(n int) D2DiagramGeneratorOption {
+	return func(d *D2DiagramGenerator) {
+		d.maxNodes = n
+	}
+}
@@ -0,0 +1,429 @@
+package lsp
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"gopkg.in/yaml.v3"
+)
+
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+):`)
+
+// document is an open concept map yaml file, parsed so that concepts and
+// propositions can be traced back to where they are written
+type document struct {
+	uri   string
+	lines []string
+	maps  []*mapDocument
+
+	// syntaxError is the yaml syntax error in the document, if any. Maps after
+	// the error are not parsed
+	syntaxError *Diagnostic
+}
+
+// mapDocument is a single concept map within a document
+type mapDocument struct {
+	cmap *conceptmap.ConceptMap
+
+	// start is the line that the map starts on
+	start int
+
+	// propositionLines are the non blank lines of the propositions block
+	propositionLines []*sourceLine
+
+	// propositionsStart and propositionsEnd are the lines spanned by the
+	// propositions block
+	propositionsStart, propositionsEnd int
+
+	// parseErrors are the errors from parsing each proposition line
+	parseErrors map[*sourceLine]error
+
+	// conceptKeys are the keys of the concepts section, in the order they are
+	// written
+	conceptKeys []*sourceLine
+
+	// conceptsIndent is the column of the keys in the concepts section, or -1 if
+	// the map has no concepts section
+	conceptsIndent int
+
+	// conceptsStart and conceptsEnd are the lines spanned by the concepts section
+	conceptsStart, conceptsEnd int
+
+	// descriptions are the lines of the map's and its concepts' descriptions
+	descriptions []*sourceLine
+}
+
+// sourceLine is a line of a scalar value in the document, along with where it is
+// written. Columns are byte offsets within the line. The lines of plain, folded
+// and quoted scalars that span several lines, or whose text is escaped, can't
+// be mapped back to where they are written, so are inexact, and positioned at
+// the start of their scalar
+type sourceLine struct {
+	text    string
+	line    int
+	column  int
+	inexact bool
+}
+
+// rangeIn returns the range spanned by the line's text in d, or the rest of the
+// line that its scalar starts on if the line is inexact
+func (l *sourceLine) rangeIn(d *document) Range {
+	if l.inexact {
+		end := l.column
+		if l.line < len(d.lines) {
+			end = len(d.lines[l.line])
+		}
+		return d.byteRange(l.line, l.column, end)
+	}
+
+	return d.byteRange(l.line, l.column, l.column+len(l.text))
+}
+
+func newDocument(uri string, text string) *document {
+	d := &document{
+		uri:   uri,
+		lines: strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"),
+	}
+
+	dec := yaml.NewDecoder(strings.NewReader(text))
+
+	for {
+		var node yaml.Node
+
+		if err := dec.Decode(&node); err != nil {
+			if !errors.Is(err, io.EOF) {
+				d.syntaxError = d.yamlErrorDiagnostic(err)
+			}
+			break
+		}
+
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+
+		d.maps = append(d.maps, d.parseMap(node.Content[0]))
+	}
+
+	return d
+}
+
+func (d *document) parseMap(node *yaml.Node) *mapDocument {
+	m := &mapDocument{
+		cmap: &conceptmap.ConceptMap{
+			Concepts:     []*conceptmap.Concept{},
+			Propositions: conceptmap.PropositionList{},
+		},
+		start:          node.Line - 1,
+		parseErrors:    map[*sourceLine]error{},
+		conceptsIndent: -1,
+	}
+
+	details := map[string]*conceptmap.Concept{}
+	parser := &conceptmap.PropositionParser{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		switch key.Value {
+		case "title":
+			m.cmap.Title = value.Value
+		case "description":
+			m.cmap.Description = value.Value
+			m.descriptions = append(m.descriptions, d.scalarLines(value)...)
+		case "propositions":
+			m.propositionsStart = key.Line - 1
+			m.propositionsEnd = d.lastLine(value)
+
+			for _, l := range d.scalarLines(value) {
+				if strings.TrimSpace(l.text) == "" {
+					continue
+				}
+
+				m.propositionLines = append(m.propositionLines, l)
+
+				if err := parser.Parse(l.text, &m.cmap.Propositions, &m.cmap.Concepts); err != nil {
+					m.parseErrors[l] = err
+				}
+			}
+		case "concepts":
+			m.conceptsStart = key.Line - 1
+			m.conceptsEnd = d.lastLine(value)
+
+			if value.Kind != yaml.MappingNode {
+				continue
+			}
+
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				k, v := value.Content[j], value.Content[j+1]
+
+				m.conceptsIndent = k.Column - 1
+				m.conceptKeys = append(m.conceptKeys, d.scalarLines(k)...)
+
+				c := new(conceptmap.Concept)
+				if err := v.Decode(c); err == nil {
+					details[k.Value] = c
+				}
+
+				for n := 0; n+1 < len(v.Content); n += 2 {
+					if v.Content[n].Value == "description" {
+						m.descriptions = append(m.descriptions, d.scalarLines(v.Content[n+1])...)
+					}
+				}
+			}
+		}
+	}
+
+	for _, c := range m.cmap.Concepts {
+		if v, ok := details[c.Label]; ok {
+			c.Aliases = v.Aliases
+			c.Description = v.Description
+			c.IsKeyConcept = v.IsKeyConcept
+			c.Tags = v.Tags
+			c.Categories = v.Categories
+			c.Group = v.Group
+		}
+	}
+
+	return m
+}
+
+// scalarLines returns the lines of a scalar node's value along with where they
+// are written. The value is split into lines as the loaders split propositions,
+// so there is a line for each proposition. Only the lines of literal block
+// scalars, and of other scalars written verbatim on a single line, are exact
+func (d *document) scalarLines(node *yaml.Node) []*sourceLine {
+	if node.Kind != yaml.ScalarNode {
+		return nil
+	}
+
+	if node.Style&yaml.LiteralStyle == 0 {
+		line := node.Line - 1
+		column := node.Column - 1
+
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			column++
+		}
+
+		exact := !strings.Contains(node.Value, "\n") && line < len(d.lines) && column <= len(d.lines[line]) &&
+			strings.HasPrefix(d.lines[line][column:], node.Value)
+
+		if exact {
+			return []*sourceLine{{text: node.Value, line: line, column: column}}
+		}
+
+		output := []*sourceLine{}
+
+		for _, text := range strings.Split(strings.TrimSuffix(node.Value, "\n"), "\n") {
+			output = append(output, &sourceLine{text: text, line: line, column: node.Column - 1, inexact: true})
+		}
+
+		return output
+	}
+
+	output := []*sourceLine{}
+
+	// The content of a literal block starts on the line after its indicator
+	for i, text := range strings.Split(strings.TrimSuffix(node.Value, "\n"), "\n") {
+		line := node.Line + i
+		column := 0
+
+		if line < len(d.lines) {
+			if idx := strings.Index(d.lines[line], text); idx >= 0 && text != "" {
+				column = idx
+			}
+		}
+
+		output = append(output, &sourceLine{text: text, line: line, column: column})
+	}
+
+	return output
+}
+
+// lastLine returns the last line spanned by node
+func (d *document) lastLine(node *yaml.Node) int {
+	last := node.Line - 1
+
+	if node.Kind == yaml.ScalarNode && node.Style&yaml.LiteralStyle != 0 {
+		last += strings.Count(strings.TrimSuffix(node.Value, "\n"), "\n") + 1
+	}
+
+	for _, child := range node.Content {
+		if l := d.lastLine(child); l > last {
+			last = l
+		}
+	}
+
+	return last
+}
+
+// yamlErrorDiagnostic converts a yaml error, which may include a line number, to a
+// diagnostic
+func (d *document) yamlErrorDiagnostic(err error) *Diagnostic {
+	line := 0
+
+	if m := yamlErrorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		line, _ = strconv.Atoi(m[1])
+		line--
+	}
+
+	return &Diagnostic{
+		Range:    d.lineRange(line),
+		Severity: SeverityError,
+		Source:   "yaml",
+		Message:  strings.TrimPrefix(err.Error(), "yaml: "),
+	}
+}
+
+// mapAt returns the map that line belongs to
+func (d *document) mapAt(line int) *mapDocument {
+	var output *mapDocument
+
+	for _, m := range d.maps {
+		if m.start <= line {
+			output = m
+		}
+	}
+
+	return output
+}
+
+// propositionLineAt returns the proposition line at line, or nil if line isn't in
+// a propositions block
+func (m *mapDocument) propositionLineAt(line int) *sourceLine {
+	for _, l := range m.propositionLines {
+		if l.line == line && !l.inexact {
+			return l
+		}
+	}
+
+	return nil
+}
+
+// inPropositions returns true if line is within the propositions block
+func (m *mapDocument) inPropositions(line int) bool {
+	return line > m.propositionsStart && line <= m.propositionsEnd || m.propositionLineAt(line) != nil
+}
+
+// inConceptKeys returns true if line is where a key of the concepts section would
+// be written
+func (m *mapDocument) inConceptKeys(d *document, line int) bool {
+	if line <= m.conceptsStart || line > m.conceptsEnd+1 || line >= len(d.lines) {
+		return false
+	}
+
+	indent := len(d.lines[line]) - len(strings.TrimLeft(d.lines[line], " "))
+
+	return indent > 0 && (m.conceptsIndent < 0 || indent == m.conceptsIndent)
+}
+
+// conceptKey returns the key in the concepts section that describes c, or nil
+func (m *mapDocument) conceptKey(c *conceptmap.Concept) *sourceLine {
+	for _, k := range m.conceptKeys {
+		if k.text == c.Label {
+			return k
+		}
+	}
+
+	return nil
+}
+
+// conceptAt returns the concept written at the given byte column of line, along
+// with the byte range it is written at. Concepts are found by label or alias,
+// preferring the longest match
+func (m *mapDocument) conceptAt(d *document, line int, column int) (*conceptmap.Concept, int, int) {
+	if line >= len(d.lines) {
+		return nil, 0, 0
+	}
+
+	text := d.lines[line]
+
+	var found *conceptmap.Concept
+	start, end := 0, 0
+
+	for _, c := range m.cmap.Concepts {
+		for _, label := range append([]string{c.Label}, c.Aliases...) {
+			for offset := 0; label != ""; {
+				idx := strings.Index(text[offset:], label)
+				if idx < 0 {
+					break
+				}
+
+				s, e := offset+idx, offset+idx+len(label)
+				offset = e
+
+				if column < s || column > e || !isWordBoundary(text, s, e) {
+					continue
+				}
+
+				if found == nil || e-s > end-start {
+					found, start, end = c, s, e
+				}
+			}
+		}
+	}
+
+	return found, start, end
+}
+
+func isWordBoundary(text string, start int, end int) bool {
+	isWordByte := func(b byte) bool {
+		return b == '_' || b == '-' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+	}
+
+	return (start == 0 || !isWordByte(text[start-1])) && (end >= len(text) || !isWordByte(text[end]))
+}
+
+// lineRange returns the range spanning the whole of line
+func (d *document) lineRange(line int) Range {
+	if line < 0 || line >= len(d.lines) {
+		return Range{Start: Position{Line: line}, End: Position{Line: line}}
+	}
+
+	return d.byteRange(line, 0, len(d.lines[line]))
+}
+
+// byteRange converts byte offsets within line to a range in utf-16 code units
+func (d *document) byteRange(line int, start int, end int) Range {
+	return Range{
+		Start: Position{Line: line, Character: d.character(line, start)},
+		End:   Position{Line: line, Character: d.character(line, end)},
+	}
+}
+
+// character converts a byte offset within line to utf-16 code units
+func (d *document) character(line int, offset int) int {
+	if line < 0 || line >= len(d.lines) {
+		return offset
+	}
+
+	text := d.lines[line]
+	if offset > len(text) {
+		offset = len(text)
+	}
+
+	return len(utf16.Encode([]rune(text[:offset])))
+}
+
+// offset converts a position, in utf-16 code units, to a byte offset within its
+// line
+func (d *document) offset(p Position) int {
+	if p.Line < 0 || p.Line >= len(d.lines) {
+		return 0
+	}
+
+	units := 0
+
+	for i, r := range d.lines[p.Line] {
+		if units >= p.Character {
+			return i
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+
+	return len(d.lines[p.Line])
+}
@@ -0,0 +1,195 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// lintRule checks a concept map in a document, returning diagnostics for any
+// problems that it finds
+type lintRule func(d *document, m *mapDocument) []Diagnostic
+
+// lintRules are run against every concept map in a document
+var lintRules = []lintRule{
+	lintMissingTitle,
+	lintParseErrors,
+	lintDuplicatePropositions,
+	lintConflictingLabels,
+	lintUnusedConcepts,
+	lintUnknownReferences,
+}
+
+// diagnostics returns the yaml syntax errors, proposition parse errors and lint
+// warnings for the document
+func (d *document) diagnostics() []Diagnostic {
+	output := []Diagnostic{}
+
+	if d.syntaxError != nil {
+		output = append(output, *d.syntaxError)
+	}
+
+	for _, m := range d.maps {
+		for _, rule := range lintRules {
+			output = append(output, rule(d, m)...)
+		}
+	}
+
+	return output
+}
+
+func lintMissingTitle(d *document, m *mapDocument) []Diagnostic {
+	if strings.TrimSpace(m.cmap.Title) != "" {
+		return nil
+	}
+
+	return []Diagnostic{d.diagnostic(d.lineRange(m.start), SeverityWarning, "concept map has no title")}
+}
+
+func lintParseErrors(d *document, m *mapDocument) []Diagnostic {
+	output := []Diagnostic{}
+
+	for _, l := range m.propositionLines {
+		if err, ok := m.parseErrors[l]; ok {
+			output = append(output, d.diagnostic(l.rangeIn(d), SeverityError, err.Error()))
+		}
+	}
+
+	return output
+}
+
+// lintDuplicatePropositions warns about propositions that repeat an earlier one,
+// which only ever add the same arrow to the diagram again
+func lintDuplicatePropositions(d *document, m *mapDocument) []Diagnostic {
+	output := []Diagnostic{}
+	seen := map[string]bool{}
+	i := 0
+
+	for _, l := range m.propositionLines {
+		if _, failed := m.parseErrors[l]; failed {
+			continue
+		}
+
+		p := m.cmap.Propositions[i]
+		i++
+
+		key := strings.Join([]string{p.Left.Key(), p.Predicate.Slug(), p.Right.Key()}, "/")
+
+		if seen[key] {
+			output = append(output, d.diagnostic(l.rangeIn(d), SeverityWarning, fmt.Sprintf("duplicate proposition '%s'", p)))
+		}
+
+		seen[key] = true
+	}
+
+	return output
+}
+
+// lintConflictingLabels warns about concepts whose labels differ, but which have
+// the same key, and so are treated as the same concept when a site is generated
+func lintConflictingLabels(d *document, m *mapDocument) []Diagnostic {
+	output := []Diagnostic{}
+	labels := map[string]string{}
+
+	for _, c := range m.cmap.Concepts {
+		other, ok := labels[c.Key()]
+		if !ok {
+			labels[c.Key()] = c.Label
+			continue
+		}
+
+		r := d.lineRange(m.start)
+		if l := m.firstUse(c); l != nil {
+			r = l.rangeIn(d)
+		}
+
+		output = append(output, d.diagnostic(r, SeverityWarning, fmt.Sprintf("concept '%s' is treated as the same concept as '%s'", c.Label, other)))
+	}
+
+	return output
+}
+
+// lintUnusedConcepts warns about concepts described in the concepts section that
+// don't appear in any proposition, as their details are ignored
+func lintUnusedConcepts(d *document, m *mapDocument) []Diagnostic {
+	output := []Diagnostic{}
+
+	for _, k := range m.conceptKeys {
+		if m.conceptWithLabel(k.text) != nil {
+			continue
+		}
+
+		msg := fmt.Sprintf("concept '%s' does not appear in any proposition", k.text)
+
+		if c := m.cmap.Concept(k.text); c != nil {
+			msg = fmt.Sprintf("concept '%s' does not appear in any proposition, did you mean '%s'?", k.text, c.Label)
+		}
+
+		output = append(output, d.diagnostic(k.rangeIn(d), SeverityWarning, msg))
+	}
+
+	return output
+}
+
+// lintUnknownReferences reports concept references in descriptions that don't
+// resolve to a concept in the map, which fail when the map is loaded
+func lintUnknownReferences(d *document, m *mapDocument) []Diagnostic {
+	output := []Diagnostic{}
+
+	for _, l := range m.descriptions {
+		for _, ref := range conceptmap.FindConceptReferences(l.text) {
+			if m.cmap.Concept(ref.Label) != nil {
+				continue
+			}
+
+			r := l.rangeIn(d)
+
+			if l.line < len(d.lines) && !l.inexact {
+				if idx := strings.Index(d.lines[l.line], "[["+ref.Label); idx >= 0 {
+					end := idx + len("[["+ref.Label)
+					if close := strings.Index(d.lines[l.line][end:], "]]"); close >= 0 {
+						end += close + 2
+					}
+					r = d.byteRange(l.line, idx, end)
+				}
+			}
+
+			output = append(output, d.diagnostic(r, SeverityError, fmt.Sprintf("unknown concept reference [[%s]]", ref.Label)))
+		}
+	}
+
+	return output
+}
+
+func (d *document) diagnostic(r Range, severity DiagnosticSeverity, message string) Diagnostic {
+	return Diagnostic{
+		Range:    r,
+		Severity: severity,
+		Source:   "conceptmapper",
+		Message:  message,
+	}
+}
+
+// conceptWithLabel returns the concept in the propositions with exactly label,
+// which is how details in the concepts section are matched to concepts
+func (m *mapDocument) conceptWithLabel(label string) *conceptmap.Concept {
+	for _, c := range m.cmap.Concepts {
+		if c.Label == label {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// firstUse returns the first proposition line that uses c
+func (m *mapDocument) firstUse(c *conceptmap.Concept) *sourceLine {
+	for _, l := range m.propositionLines {
+		if idx := strings.Index(l.text, c.Label); idx >= 0 && isWordBoundary(l.text, idx, idx+len(c.Label)) {
+			return l
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package lsp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Diagnostic
+	}{
+		{
+			name: "duplicate in literal block",
+			text: "title: Test\npropositions: |\n  Pod runs Container\n  Pod runs Container\n",
+			want: []Diagnostic{
+				{Range: lineRange(3, 2, 20), Severity: SeverityWarning, Message: "duplicate proposition 'Pod runs Container'"},
+			},
+		},
+		{
+			name: "parse error in literal block",
+			text: "title: Test\npropositions: |\n  Pod runs Container\n  pod runs\n",
+			want: []Diagnostic{
+				{Range: lineRange(3, 2, 10), Severity: SeverityError, Message: "could not find left concept in proposition 'pod runs'"},
+			},
+		},
+		{
+			name: "single line plain scalar",
+			text: "title: Test\npropositions: Pod runs\n",
+			want: []Diagnostic{
+				{Range: lineRange(1, 14, 22), Severity: SeverityError, Message: "could not find right concept in proposition 'Pod runs'"},
+			},
+		},
+		{
+			name: "duplicate in double quoted scalar",
+			text: "title: Test\npropositions: \"Pod runs Container\\nPod runs Container\"\n",
+			want: []Diagnostic{
+				{Range: lineRange(1, 14, 54), Severity: SeverityWarning, Message: "duplicate proposition 'Pod runs Container'"},
+			},
+		},
+		{
+			name: "duplicate in folded block",
+			text: "title: Test\npropositions: >\n  Pod runs Container\n\n  Pod runs Container\n",
+			want: []Diagnostic{
+				{Range: lineRange(1, 14, 15), Severity: SeverityWarning, Message: "duplicate proposition 'Pod runs Container'"},
+			},
+		},
+		{
+			name: "plain scalar spanning lines is one proposition",
+			text: "title: Test\npropositions: Pod runs\n  Container\n",
+			want: []Diagnostic{},
+		},
+		{
+			name: "unused concept",
+			text: "title: Test\npropositions: |\n  Pod runs Container\nconcepts:\n  Node:\n    description: A machine\n",
+			want: []Diagnostic{
+				{Range: lineRange(4, 2, 6), Severity: SeverityWarning, Message: "concept 'Node' does not appear in any proposition"},
+			},
+		},
+		{
+			name: "unknown reference",
+			text: "title: Test\ndescription: Runs [[Nodes]] and [[Pod]]\npropositions: |\n  Pod runs Container\n",
+			want: []Diagnostic{
+				{Range: lineRange(1, 18, 27), Severity: SeverityError, Message: "unknown concept reference [[Nodes]]"},
+			},
+		},
+		{
+			name: "missing title",
+			text: "propositions: |\n  Pod runs Container\n",
+			want: []Diagnostic{
+				{Range: lineRange(0, 0, 15), Severity: SeverityWarning, Message: "concept map has no title"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newDocument("file:///test.yaml", tt.text).diagnostics()
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d diagnostics, want %d: %+v", len(got), len(tt.want), got)
+			}
+
+			for i, want := range tt.want {
+				want.Source = "conceptmapper"
+
+				if got[i] != want {
+					t.Errorf("got %+v, want %+v", got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestScalarLinesAreOnePerProposition(t *testing.T) {
+	d := newDocument("file:///test.yaml", "title: Test\npropositions: \"Pod runs Container\\nNode runs Pod\"\n")
+
+	if len(d.maps) != 1 {
+		t.Fatalf("expected 1 map, got %d", len(d.maps))
+	}
+
+	m := d.maps[0]
+
+	if len(m.propositionLines) != len(m.cmap.Propositions) {
+		t.Fatalf("got %d proposition lines for %d propositions", len(m.propositionLines), len(m.cmap.Propositions))
+	}
+
+	for _, l := range m.propositionLines {
+		if !l.inexact {
+			t.Errorf("expected line %q of a quoted scalar spanning several lines to be inexact", l.text)
+		}
+
+		if strings.Contains(l.text, "\n") {
+			t.Errorf("expected line %q to be a single line", l.text)
+		}
+	}
+
+	if l := m.propositionLineAt(1); l != nil {
+		t.Errorf("expected no exact proposition line at line 1, got %q", l.text)
+	}
+}
+
+func lineRange(line, start, end int) Range {
+	return Range{
+		Start: Position{Line: line, Character: start},
+		End:   Position{Line: line, Character: end},
+	}
+}
@@ -0,0 +1,387 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// Server is a language server for concept map yaml files. It publishes
+// diagnostics for open documents, and provides completion of concept labels and
+// predicates, hover information for concepts and go to definition into the
+// concepts section of a map
+type Server struct {
+	conn      *conn
+	documents map[string]*document
+}
+
+// NewServer creates a Server that reads requests from r and writes responses to
+// w, such as stdin and stdout
+func NewServer(r io.Reader, w io.Writer) *Server {
+	return &Server{
+		conn:      newConn(r, w),
+		documents: map[string]*document{},
+	}
+}
+
+// Serve handles requests until the client sends an exit notification, the input
+// is closed or ctx is done
+func (s *Server) Serve(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		msg, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			var rerr *responseError
+			if errors.As(err, &rerr) {
+				if err := s.conn.reply(nil, nil, rerr); err != nil {
+					return err
+				}
+				continue
+			}
+
+			return err
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		result, err := s.handle(msg)
+
+		// Notifications don't have an id, and must not be replied to
+		if msg.ID == nil {
+			continue
+		}
+
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) (interface{}, error) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": 1,
+				"completionProvider": map[string]interface{}{
+					"triggerCharacters": []string{"["},
+				},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]string{
+				"name": "conceptmapper",
+			},
+		}, nil
+
+	case "initialized", "shutdown":
+		return nil, nil
+
+	case "textDocument/didOpen":
+		params := new(didOpenTextDocumentParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, err
+		}
+
+		return nil, s.open(params.TextDocument.URI, params.TextDocument.Text)
+
+	case "textDocument/didChange":
+		params := new(didChangeTextDocumentParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, err
+		}
+
+		// The server asks for full document sync, so the last change is the whole
+		// document
+		if n := len(params.ContentChanges); n > 0 {
+			return nil, s.open(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+		}
+
+		return nil, nil
+
+	case "textDocument/didClose":
+		params := new(didCloseTextDocumentParams)
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, err
+		}
+
+		delete(s.documents, params.TextDocument.URI)
+
+		return nil, s.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+
+	case "textDocument/completion":
+		d, p, err := s.position(msg.Params)
+		if err != nil || d == nil {
+			return nil, err
+		}
+
+		return d.completions(p), nil
+
+	case "textDocument/hover":
+		d, p, err := s.position(msg.Params)
+		if err != nil || d == nil {
+			return nil, err
+		}
+
+		if h := d.hover(p); h != nil {
+			return h, nil
+		}
+
+		return nil, nil
+
+	case "textDocument/definition":
+		d, p, err := s.position(msg.Params)
+		if err != nil || d == nil {
+			return nil, err
+		}
+
+		if l := d.definition(p); l != nil {
+			return l, nil
+		}
+
+		return nil, nil
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method '%s' not found", msg.Method)}
+}
+
+// open parses text as the content of the document at uri, and publishes its
+// diagnostics
+func (s *Server) open(uri string, text string) error {
+	d := newDocument(uri, text)
+	s.documents[uri] = d
+
+	return s.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: d.diagnostics(),
+	})
+}
+
+// position returns the open document and position that a request refers to
+func (s *Server) position(raw json.RawMessage) (*document, Position, error) {
+	params := new(textDocumentPositionParams)
+	if err := json.Unmarshal(raw, params); err != nil {
+		return nil, Position{}, err
+	}
+
+	return s.documents[params.TextDocument.URI], params.Position, nil
+}
+
+// completions returns the concept labels and predicates that could be written at
+// p. Concepts are completed in propositions, concept references and the keys of
+// the concepts section, and predicates in propositions
+func (d *document) completions(p Position) []CompletionItem {
+	output := []CompletionItem{}
+
+	m := d.mapAt(p.Line)
+	if m == nil || p.Line >= len(d.lines) {
+		return output
+	}
+
+	prefix := d.lines[p.Line][:d.offset(p)]
+
+	if idx := strings.LastIndex(prefix, "[["); idx >= 0 && !strings.Contains(prefix[idx:], "]]") {
+		return d.conceptCompletions(m, nil)
+	}
+
+	if m.inConceptKeys(d, p.Line) {
+		described := map[string]bool{}
+		for _, k := range m.conceptKeys {
+			if k.line != p.Line {
+				described[k.text] = true
+			}
+		}
+
+		return d.conceptCompletions(m, described)
+	}
+
+	if !m.inPropositions(p.Line) {
+		return output
+	}
+
+	// Work out which part of the proposition is being written from the words
+	// before the one under the cursor
+	words := strings.Fields(prefix)
+	if len(words) > 0 && !strings.HasSuffix(prefix, " ") {
+		words = words[:len(words)-1]
+	}
+
+	state := 1 // 1: left concept, 2: predicate, 3: right concept
+
+	for _, w := range words {
+		lower := unicode.IsLower([]rune(w)[0])
+
+		switch {
+		case state == 1 && lower:
+			state = 2
+		case state == 2 && !lower:
+			state = 3
+		}
+	}
+
+	if state == 2 || state == 1 && len(words) > 0 {
+		output = append(output, d.predicateCompletions()...)
+	}
+
+	if state != 2 || len(words) > 0 {
+		output = append(output, d.conceptCompletions(m, nil)...)
+	}
+
+	return output
+}
+
+// conceptCompletions returns the labels of the concepts in m, followed by those in
+// other maps in the document, excluding those in exclude
+func (d *document) conceptCompletions(m *mapDocument, exclude map[string]bool) []CompletionItem {
+	output := []CompletionItem{}
+	seen := map[string]bool{}
+
+	maps := append([]*mapDocument{m}, d.maps...)
+
+	for _, other := range maps {
+		for _, c := range other.cmap.Concepts {
+			if seen[c.Label] || exclude[c.Label] {
+				continue
+			}
+
+			seen[c.Label] = true
+
+			detail := "concept"
+			if other != m {
+				detail = fmt.Sprintf("concept in %s", other.cmap.Title)
+			}
+
+			output = append(output, CompletionItem{
+				Label:  c.Label,
+				Kind:   CompletionItemKindClass,
+				Detail: detail,
+			})
+		}
+	}
+
+	return output
+}
+
+// predicateCompletions returns the distinct predicates used in the document
+func (d *document) predicateCompletions() []CompletionItem {
+	output := []CompletionItem{}
+	seen := map[string]bool{}
+
+	for _, m := range d.maps {
+		for _, p := range m.cmap.Propositions.Predicates() {
+			if seen[p.Slug()] {
+				continue
+			}
+
+			seen[p.Slug()] = true
+
+			output = append(output, CompletionItem{
+				Label:  string(p),
+				Kind:   CompletionItemKindOperator,
+				Detail: "predicate",
+			})
+		}
+	}
+
+	return output
+}
+
+// hover describes the concept at p
+func (d *document) hover(p Position) *Hover {
+	m := d.mapAt(p.Line)
+	if m == nil {
+		return nil
+	}
+
+	c, start, end := m.conceptAt(d, p.Line, d.offset(p))
+	if c == nil {
+		return nil
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "**%s**", c.Label)
+
+	if c.IsKeyConcept {
+		b.WriteString(" _(key concept)_")
+	}
+
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(&b, "\n\nAlso known as %s", strings.Join(c.Aliases, ", "))
+	}
+
+	if strings.TrimSpace(c.Description) != "" {
+		fmt.Fprintf(&b, "\n\n%s", strings.TrimSpace(c.Description))
+	}
+
+	if len(c.Tags) > 0 {
+		fmt.Fprintf(&b, "\n\nTags: %s", strings.Join(c.Tags, ", "))
+	}
+
+	if ps := m.cmap.Propositions.InvolvingConcepts(c); len(ps) > 0 {
+		b.WriteString("\n")
+
+		for _, prop := range ps {
+			fmt.Fprintf(&b, "\n- %s", prop)
+		}
+	}
+
+	r := d.byteRange(p.Line, start, end)
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: b.String()},
+		Range:    &r,
+	}
+}
+
+// definition returns the location of the concepts section entry describing the
+// concept at p, or the first proposition that uses it if it isn't described
+func (d *document) definition(p Position) *Location {
+	m := d.mapAt(p.Line)
+	if m == nil {
+		return nil
+	}
+
+	c, _, _ := m.conceptAt(d, p.Line, d.offset(p))
+	if c == nil {
+		return nil
+	}
+
+	l := m.conceptKey(c)
+	if l == nil {
+		l = m.firstUse(c)
+	}
+
+	if l == nil {
+		return nil
+	}
+
+	return &Location{URI: d.uri, Range: d.locationRange(l, c)}
+}
+
+// locationRange returns the range of c's label within l
+func (d *document) locationRange(l *sourceLine, c *conceptmap.Concept) Range {
+	if idx := strings.Index(l.text, c.Label); idx >= 0 && !l.inexact {
+		return d.byteRange(l.line, l.column+idx, l.column+idx+len(c.Label))
+	}
+
+	return l.rangeIn(d)
+}
@@ -0,0 +1,124 @@
+package network
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// GEXFExporter writes concept maps in GEXF format
+var GEXFExporter conceptmap.Exporter = conceptmap.ExporterFunc(ExportGEXF)
+
+// Ids of the node attributes in exported GEXF files
+const (
+	gexfDescription = "description"
+	gexfKeyConcept  = "keyConcept"
+	gexfTags        = "tags"
+)
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator     string `xml:"creator"`
+	Description string `xml:"description,omitempty"`
+}
+
+type gexfGraph struct {
+	Mode            string         `xml:"mode,attr"`
+	DefaultEdgeType string         `xml:"defaultedgetype,attr"`
+	Attributes      gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode     `xml:"nodes>node"`
+	Edges           []gexfEdge     `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue,omitempty"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Label  string `xml:"label,attr"`
+}
+
+// ExportGEXF writes maps to w as a single static, directed GEXF 1.2 graph
+func ExportGEXF(w io.Writer, maps []*conceptmap.ConceptMap) error {
+	n := newNetwork(maps)
+
+	doc := &gexfDocument{
+		XMLNS:   "http://www.gexf.net/1.2draft",
+		Version: "1.2",
+		Meta: gexfMeta{
+			Creator:     "conceptmapper",
+			Description: n.title,
+		},
+		Graph: gexfGraph{
+			Mode:            "static",
+			DefaultEdgeType: "directed",
+			Attributes: gexfAttributes{
+				Class: "node",
+				Attributes: []gexfAttribute{
+					{ID: gexfDescription, Title: "description", Type: "string"},
+					{ID: gexfKeyConcept, Title: "keyConcept", Type: "boolean"},
+					{ID: gexfTags, Title: "tags", Type: "string"},
+				},
+			},
+		},
+	}
+
+	for _, nd := range n.nodes {
+		values := []gexfAttValue{
+			{For: gexfKeyConcept, Value: strconv.FormatBool(nd.keyConcept)},
+		}
+
+		if nd.description != "" {
+			values = append(values, gexfAttValue{For: gexfDescription, Value: nd.description})
+		}
+
+		if len(nd.tags) > 0 {
+			values = append(values, gexfAttValue{For: gexfTags, Value: strings.Join(nd.tags, tagSeparator)})
+		}
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{ID: nd.id, Label: nd.label, AttValues: values})
+	}
+
+	for i, e := range n.edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:     fmt.Sprintf("%d", i),
+			Source: e.source,
+			Target: e.target,
+			Label:  e.label,
+		})
+	}
+
+	return writeXML(w, doc)
+}
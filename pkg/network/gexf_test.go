@@ -0,0 +1,65 @@
+package network
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportGEXF(t *testing.T) {
+	var doc gexfDocument
+
+	export(t, GEXFExporter, &doc)
+
+	labels := map[string]string{}
+	values := map[string]map[string]string{}
+	ids := []string{}
+
+	for _, n := range doc.Graph.Nodes {
+		labels[n.ID] = n.Label
+		values[n.ID] = map[string]string{}
+
+		for _, v := range n.AttValues {
+			values[n.ID][v.For] = v.Value
+		}
+
+		ids = append(ids, n.ID)
+	}
+
+	if got, want := strings.Join(ids, ","), "kubernetes,pods,containers,deployments"; got != want {
+		t.Errorf("got nodes %s, want %s", got, want)
+	}
+
+	if labels["pods"] != "Pods" {
+		t.Errorf("expected pods to be labelled Pods, got %q", labels["pods"])
+	}
+
+	want := map[string]string{
+		gexfDescription: "The smallest unit",
+		gexfKeyConcept:  "true",
+		gexfTags:        "Workloads|Scaling",
+	}
+
+	for k, v := range want {
+		if values["pods"][k] != v {
+			t.Errorf("expected pods to have %s %q, got %q", k, v, values["pods"][k])
+		}
+	}
+
+	if values["kubernetes"][gexfKeyConcept] != "false" {
+		t.Errorf("expected kubernetes not to be a key concept, got %q", values["kubernetes"][gexfKeyConcept])
+	}
+
+	edges := []string{}
+
+	for _, e := range doc.Graph.Edges {
+		edges = append(edges, e.Source+" "+e.Label+" "+e.Target)
+	}
+
+	if got, want := strings.Join(edges, "\n"), "kubernetes runs pods\npods contain containers\ndeployments manage pods"; got != want {
+		t.Errorf("got edges\n%s\nwant\n%s", got, want)
+	}
+
+	if doc.Meta.Description != "Kubernetes, Deployments" {
+		t.Errorf("expected the graph to be described by both maps' titles, got %q", doc.Meta.Description)
+	}
+}
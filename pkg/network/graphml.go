@@ -0,0 +1,123 @@
+package network
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// GraphMLExporter writes concept maps in GraphML format
+var GraphMLExporter conceptmap.Exporter = conceptmap.ExporterFunc(ExportGraphML)
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphMLData `xml:"data"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ExportGraphML writes maps to w as a single directed GraphML graph. Node and
+// edge attributes are declared as GraphML keys, with edge labels in the label key
+func ExportGraphML(w io.Writer, maps []*conceptmap.ConceptMap) error {
+	n := newNetwork(maps)
+
+	doc := &graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "title", For: "graph", Name: "title", Type: "string"},
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "description", For: "node", Name: "description", Type: "string"},
+			{ID: "keyConcept", For: "node", Name: "keyConcept", Type: "boolean"},
+			{ID: "tags", For: "node", Name: "tags", Type: "string"},
+			{ID: "edgeLabel", For: "edge", Name: "label", Type: "string"},
+		},
+		Graph: graphMLGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+		},
+	}
+
+	if n.title != "" {
+		doc.Graph.Data = append(doc.Graph.Data, graphMLData{Key: "title", Value: n.title})
+	}
+
+	for _, nd := range n.nodes {
+		data := []graphMLData{
+			{Key: "label", Value: nd.label},
+			{Key: "keyConcept", Value: strconv.FormatBool(nd.keyConcept)},
+		}
+
+		if nd.description != "" {
+			data = append(data, graphMLData{Key: "description", Value: nd.description})
+		}
+
+		if len(nd.tags) > 0 {
+			data = append(data, graphMLData{Key: "tags", Value: strings.Join(nd.tags, tagSeparator)})
+		}
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: nd.id, Data: data})
+	}
+
+	for i, e := range n.edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: e.source,
+			Target: e.target,
+			Data:   []graphMLData{{Key: "edgeLabel", Value: e.label}},
+		})
+	}
+
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,113 @@
+package network
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// testMaps share Pods and the proposition Kubernetes runs Pods, so that the
+// exported network merges them
+const testMaps = `title: Kubernetes
+propositions: |
+  Kubernetes runs Pods
+  Pods contain Containers
+concepts:
+  Pods:
+    description: The smallest unit
+    isKeyConcept: true
+    tags: [Workloads]
+---
+title: Deployments
+propositions: |
+  Deployments | manage | pods
+  Kubernetes runs Pods
+concepts:
+  pods:
+    tags: [Workloads, Scaling]
+`
+
+func export(t *testing.T, exporter conceptmap.Exporter, v interface{}) {
+	t.Helper()
+
+	maps, err := conceptmap.LoadFromYamlReader(strings.NewReader(testMaps))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+
+	if err := exporter.Export(&b, maps); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := xml.Unmarshal(b.Bytes(), v); err != nil {
+		t.Fatalf("%s\n%s", err, b.String())
+	}
+}
+
+func TestExportGraphML(t *testing.T) {
+	var doc graphMLDocument
+
+	export(t, GraphMLExporter, &doc)
+
+	nodes := map[string]map[string]string{}
+	ids := []string{}
+
+	for _, n := range doc.Graph.Nodes {
+		data := map[string]string{}
+
+		for _, d := range n.Data {
+			data[d.Key] = d.Value
+		}
+
+		nodes[n.ID] = data
+		ids = append(ids, n.ID)
+	}
+
+	if got, want := strings.Join(ids, ","), "kubernetes,pods,containers,deployments"; got != want {
+		t.Errorf("got nodes %s, want %s", got, want)
+	}
+
+	want := map[string]string{
+		"label":       "Pods",
+		"description": "The smallest unit",
+		"keyConcept":  "true",
+		"tags":        "Workloads|Scaling",
+	}
+
+	for k, v := range want {
+		if nodes["pods"][k] != v {
+			t.Errorf("expected pods to have %s %q, got %q", k, v, nodes["pods"][k])
+		}
+	}
+
+	if nodes["kubernetes"]["keyConcept"] != "false" {
+		t.Errorf("expected kubernetes not to be a key concept, got %q", nodes["kubernetes"]["keyConcept"])
+	}
+
+	edges := []string{}
+
+	for _, e := range doc.Graph.Edges {
+		label := ""
+
+		for _, d := range e.Data {
+			if d.Key == "edgeLabel" {
+				label = d.Value
+			}
+		}
+
+		edges = append(edges, e.Source+" "+label+" "+e.Target)
+	}
+
+	if got, want := strings.Join(edges, "\n"), "kubernetes runs pods\npods contain containers\ndeployments manage pods"; got != want {
+		t.Errorf("got edges\n%s\nwant\n%s", got, want)
+	}
+
+	if len(doc.Graph.Data) != 1 || doc.Graph.Data[0].Value != "Kubernetes, Deployments" {
+		t.Errorf("expected the graph to be titled after both maps, got %+v", doc.Graph.Data)
+	}
+}
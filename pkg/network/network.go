@@ -0,0 +1,100 @@
+// Package network exports concept maps as networks in GraphML and GEXF, so that
+// they can be opened in network analysis tools such as Gephi and yEd.
+//
+// Concepts become nodes, identified by their key, with their label, description,
+// whether they are key concepts and their tags as attributes. Propositions become
+// directed edges labelled with their predicate. Concepts with the same key in
+// different maps are the same node
+package network
+
+import (
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// tagSeparator separates the tags of a concept in the tags attribute, as neither
+// format has a portable list type
+const tagSeparator = "|"
+
+type node struct {
+	id          string
+	label       string
+	description string
+	keyConcept  bool
+	tags        []string
+}
+
+type edge struct {
+	source string
+	target string
+	label  string
+}
+
+type network struct {
+	title string
+	nodes []*node
+	edges []*edge
+}
+
+// newNetwork merges maps into a single network. Details of concepts that appear
+// in several maps are combined, and propositions that appear in several maps
+// become a single edge
+func newNetwork(maps []*conceptmap.ConceptMap) *network {
+	n := &network{}
+	nodes := map[string]*node{}
+	edges := map[edge]bool{}
+	titles := []string{}
+
+	for _, m := range maps {
+		if m.Title != "" {
+			titles = append(titles, m.Title)
+		}
+
+		for _, c := range m.Concepts {
+			nd, ok := nodes[c.Key()]
+			if !ok {
+				nd = &node{id: c.Key(), label: c.Label}
+				nodes[c.Key()] = nd
+				n.nodes = append(n.nodes, nd)
+			}
+
+			if nd.description == "" {
+				nd.description = strings.TrimSpace(c.Description)
+			}
+
+			nd.keyConcept = nd.keyConcept || c.IsKeyConcept
+
+			for _, t := range c.Tags {
+				if !containsString(nd.tags, t) {
+					nd.tags = append(nd.tags, t)
+				}
+			}
+		}
+
+		for _, p := range m.Propositions {
+			e := edge{source: p.Left.Key(), target: p.Right.Key(), label: string(p.Predicate)}
+
+			if edges[e] {
+				continue
+			}
+
+			edges[e] = true
+			n.edges = append(n.edges, &e)
+		}
+	}
+
+	n.title = strings.Join(titles, ", ")
+
+	return n
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
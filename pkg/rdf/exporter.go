@@ -0,0 +1,175 @@
+// Package rdf exports concept maps as rdf graphs, so that they can be loaded into
+// a triple store and queried across maps.
+//
+// Concepts become resources, labelled with rdfs:label and described with
+// rdfs:comment, and each predicate becomes a property whose iri is its slug. Each
+// proposition becomes a triple connecting two concepts with a predicate. Concepts
+// with the same key in different maps are the same resource.
+//
+// Predicates with the same slug, such as "Runs" and "runs", are the same
+// property. It is labelled with the first spelling used, and any other
+// spellings are kept as cm:alias, as concepts' aliases are
+package rdf
+
+import (
+	"io"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// DefaultBaseIRI is the iri that exported resources are named relative to, unless
+// another is set with WithBaseIRI
+const DefaultBaseIRI = "https://example.org/conceptmapper/"
+
+// Exporter writes concept maps as rdf in some serialisation
+type Exporter struct {
+	baseIRI string
+	skos    map[string]SKOSRelation
+	write   func(io.Writer, *graph) error
+}
+
+type ExporterOption func(*Exporter)
+
+// WithBaseIRI sets the iri that concepts, predicates and maps are named relative
+// to. Concepts are named base/concept/<key>, predicates base/predicate/<slug>,
+// maps base/map/<slug> and the conceptmapper vocabulary base/ns#
+func WithBaseIRI(iri string) ExporterOption {
+	return func(e *Exporter) {
+		if iri != "" && !strings.HasSuffix(iri, "/") && !strings.HasSuffix(iri, "#") {
+			iri += "/"
+		}
+		e.baseIRI = iri
+	}
+}
+
+// NewTurtleExporter creates an Exporter that writes turtle
+func NewTurtleExporter(opts ...ExporterOption) *Exporter {
+	return newExporter(writeTurtle, opts...)
+}
+
+// NewJSONLDExporter creates an Exporter that writes json-ld
+func NewJSONLDExporter(opts ...ExporterOption) *Exporter {
+	return newExporter(writeJSONLD, opts...)
+}
+
+func newExporter(write func(io.Writer, *graph) error, opts ...ExporterOption) *Exporter {
+	e := &Exporter{
+		baseIRI: DefaultBaseIRI,
+		write:   write,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.baseIRI == "" {
+		e.baseIRI = DefaultBaseIRI
+	}
+
+	return e
+}
+
+// Export writes maps to w
+func (e *Exporter) Export(w io.Writer, maps []*conceptmap.ConceptMap) error {
+	if e.skos != nil {
+		return e.write(w, e.skosGraph(maps))
+	}
+
+	return e.write(w, e.graph(maps))
+}
+
+// Namespaces of the exported resources
+func (e *Exporter) vocabulary() string { return e.baseIRI + "ns#" }
+func (e *Exporter) concepts() string   { return e.baseIRI + "concept/" }
+func (e *Exporter) predicates() string { return e.baseIRI + "predicate/" }
+func (e *Exporter) maps() string       { return e.baseIRI + "map/" }
+
+func (e *Exporter) graph(maps []*conceptmap.ConceptMap) *graph {
+	g := &graph{
+		prefixes: []prefix{
+			{name: "rdf", iri: RDF},
+			{name: "rdfs", iri: RDFS},
+			{name: "xsd", iri: XSD},
+			{name: "cm", iri: e.vocabulary()},
+			{name: "concept", iri: e.concepts()},
+			{name: "predicate", iri: e.predicates()},
+			{name: "map", iri: e.maps()},
+		},
+	}
+
+	cm := e.vocabulary()
+	described := map[string]bool{}
+
+	// Spellings of predicates that have been labelled or aliased
+	spellings := map[string]bool{}
+
+	for _, m := range maps {
+		mapIRI := e.maps() + m.Slug()
+
+		g.add(mapIRI, RDF+"type", iri(cm+"ConceptMap"))
+		g.add(mapIRI, RDFS+"label", literal(m.Title))
+
+		if d := strings.TrimSpace(m.Description); d != "" {
+			g.add(mapIRI, RDFS+"comment", literal(d))
+		}
+
+		for _, c := range m.Concepts {
+			g.add(mapIRI, cm+"hasConcept", iri(e.concepts()+c.Key()))
+		}
+
+		for _, p := range m.Propositions {
+			predicateIRI := e.predicates() + p.Predicate.Slug()
+
+			if !described[predicateIRI] {
+				described[predicateIRI] = true
+				spellings[string(p.Predicate)] = true
+
+				g.add(predicateIRI, RDF+"type", iri(RDF+"Property"))
+				g.add(predicateIRI, RDFS+"label", literal(string(p.Predicate)))
+				continue
+			}
+
+			if !spellings[string(p.Predicate)] {
+				spellings[string(p.Predicate)] = true
+				g.add(predicateIRI, cm+"alias", literal(string(p.Predicate)))
+			}
+		}
+
+		for _, c := range m.Concepts {
+			conceptIRI := e.concepts() + c.Key()
+
+			if !described[conceptIRI] {
+				described[conceptIRI] = true
+
+				g.add(conceptIRI, RDF+"type", iri(cm+"Concept"))
+				g.add(conceptIRI, RDFS+"label", literal(c.Label))
+			}
+
+			// Details may be given in any map that contains the concept
+			if d := strings.TrimSpace(c.Description); d != "" {
+				g.add(conceptIRI, RDFS+"comment", literal(d))
+			}
+
+			if c.IsKeyConcept {
+				g.add(conceptIRI, cm+"isKeyConcept", typedLiteral("true", XSD+"boolean"))
+			}
+
+			for _, a := range c.Aliases {
+				g.add(conceptIRI, cm+"alias", literal(a))
+			}
+
+			for _, t := range c.Tags {
+				g.add(conceptIRI, cm+"tag", literal(t))
+			}
+
+			for _, p := range m.Propositions {
+				if p.Left.Key() == c.Key() {
+					g.add(conceptIRI, e.predicates()+p.Predicate.Slug(), iri(e.concepts()+p.Right.Key()))
+				}
+			}
+		}
+	}
+
+	return g.distinct()
+}
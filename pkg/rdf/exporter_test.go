@@ -0,0 +1,44 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+func TestPredicatesWithTheSameSlugHaveOneLabel(t *testing.T) {
+	maps, err := conceptmap.LoadFromYamlReader(strings.NewReader(
+		"title: T\npropositions: |\n  A | Runs | B\n  A runs C\n---\ntitle: U\npropositions: |\n  D | RUNS | E\n  D runs E\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewTurtleExporter()
+	g := e.graph(maps)
+	subject := e.predicates() + "runs"
+
+	labels := []string{}
+	aliases := []string{}
+
+	for _, tr := range g.triples {
+		if tr.subject != subject {
+			continue
+		}
+
+		switch tr.predicate {
+		case RDFS + "label":
+			labels = append(labels, tr.object.value)
+		case e.vocabulary() + "alias":
+			aliases = append(aliases, tr.object.value)
+		}
+	}
+
+	if strings.Join(labels, ",") != "Runs" {
+		t.Errorf("expected the single label Runs, got %v", labels)
+	}
+
+	if strings.Join(aliases, ",") != "runs,RUNS" {
+		t.Errorf("expected the aliases runs and RUNS, got %v", aliases)
+	}
+}
@@ -0,0 +1,114 @@
+package rdf
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Namespaces of the standard vocabularies used in exported graphs
+const (
+	RDF  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	RDFS = "http://www.w3.org/2000/01/rdf-schema#"
+	XSD  = "http://www.w3.org/2001/XMLSchema#"
+)
+
+var localNamePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]*$`)
+
+// term is the object of a triple, either an iri or a literal
+type term struct {
+	iri      string
+	value    string
+	datatype string
+	literal  bool
+}
+
+func iri(s string) term {
+	return term{iri: s}
+}
+
+func literal(s string) term {
+	return term{value: s, literal: true}
+}
+
+func typedLiteral(s string, datatype string) term {
+	return term{value: s, datatype: datatype, literal: true}
+}
+
+type triple struct {
+	subject   string
+	predicate string
+	object    term
+}
+
+type prefix struct {
+	name string
+	iri  string
+}
+
+// graph is a list of triples, along with the prefixes used to abbreviate iris
+// when it is written
+type graph struct {
+	prefixes []prefix
+	triples  []triple
+}
+
+func (g *graph) add(subject string, predicate string, object term) {
+	g.triples = append(g.triples, triple{subject: subject, predicate: predicate, object: object})
+}
+
+// subjects returns the distinct subjects in the graph, in the order they are
+// first used
+func (g *graph) subjects() []string {
+	output := []string{}
+	seen := map[string]bool{}
+
+	for _, t := range g.triples {
+		if !seen[t.subject] {
+			seen[t.subject] = true
+			output = append(output, t.subject)
+		}
+	}
+
+	return output
+}
+
+// about returns the triples whose subject is subject
+func (g *graph) about(subject string) []triple {
+	output := []triple{}
+
+	for _, t := range g.triples {
+		if t.subject == subject {
+			output = append(output, t)
+		}
+	}
+
+	return output
+}
+
+// compact abbreviates s using the graph's prefixes, returning false if none of
+// them apply
+func (g *graph) compact(s string) (string, bool) {
+	for _, p := range g.prefixes {
+		if local := strings.TrimPrefix(s, p.iri); local != s && localNamePattern.MatchString(local) {
+			return p.name + ":" + local, true
+		}
+	}
+
+	return s, false
+}
+
+// distinct returns a copy of the graph without duplicate triples, which occur
+// when the same concept or proposition appears in several maps
+func (g *graph) distinct() *graph {
+	output := &graph{prefixes: g.prefixes}
+	seen := map[triple]bool{}
+
+	for _, t := range g.triples {
+		if !seen[t] {
+			seen[t] = true
+			output.triples = append(output.triples, t)
+		}
+	}
+
+	return output
+}
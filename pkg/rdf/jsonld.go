@@ -0,0 +1,74 @@
+package rdf
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// writeJSONLD writes g as a compacted json-ld document, with a node object for
+// each subject in its @graph
+func writeJSONLD(w io.Writer, g *graph) error {
+	context := map[string]string{}
+
+	for _, p := range g.prefixes {
+		context[p.name] = p.iri
+	}
+
+	nodes := []map[string]interface{}{}
+
+	for _, s := range g.subjects() {
+		node := map[string]interface{}{"@id": jsonldIRI(g, s)}
+
+		for _, t := range g.about(s) {
+			key := jsonldIRI(g, t.predicate)
+
+			var value interface{}
+
+			if t.predicate == RDF+"type" {
+				key = "@type"
+				value = jsonldIRI(g, t.object.iri)
+			} else {
+				value = jsonldTerm(g, t.object)
+			}
+
+			switch existing := node[key].(type) {
+			case nil:
+				node[key] = value
+			case []interface{}:
+				node[key] = append(existing, value)
+			default:
+				node[key] = []interface{}{existing, value}
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(map[string]interface{}{
+		"@context": context,
+		"@graph":   nodes,
+	})
+}
+
+func jsonldIRI(g *graph, s string) string {
+	c, _ := g.compact(s)
+	return c
+}
+
+func jsonldTerm(g *graph, t term) interface{} {
+	if !t.literal {
+		return map[string]string{"@id": jsonldIRI(g, t.iri)}
+	}
+
+	switch t.datatype {
+	case "":
+		return t.value
+	case XSD + "boolean":
+		return t.value == "true"
+	}
+
+	return map[string]string{"@value": t.value, "@type": jsonldIRI(g, t.datatype)}
+}
@@ -0,0 +1,155 @@
+package rdf
+
+import (
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/gosimple/slug"
+)
+
+// SKOS is the namespace of the skos vocabulary
+const SKOS = "http://www.w3.org/2004/02/skos/core#"
+
+// SKOSRelation is the skos semantic relation that a predicate is exported as
+type SKOSRelation string
+
+const (
+	// SKOSBroader means the left concept is narrower than the right, as in
+	// "Pod is a Workload"
+	SKOSBroader SKOSRelation = "broader"
+
+	// SKOSNarrower means the left concept is broader than the right, as in
+	// "Workload includes Pod"
+	SKOSNarrower SKOSRelation = "narrower"
+
+	// SKOSRelated means the concepts are associated, without one being broader
+	SKOSRelated SKOSRelation = "related"
+)
+
+// DefaultSKOSMapping maps common hierarchical predicates to skos relations
+var DefaultSKOSMapping = map[string]SKOSRelation{
+	"is a":           SKOSBroader,
+	"is an":          SKOSBroader,
+	"is a kind of":   SKOSBroader,
+	"is a type of":   SKOSBroader,
+	"is part of":     SKOSBroader,
+	"are":            SKOSBroader,
+	"are a kind of":  SKOSBroader,
+	"are part of":    SKOSBroader,
+	"includes":       SKOSNarrower,
+	"include":        SKOSNarrower,
+	"has part":       SKOSNarrower,
+	"has kind":       SKOSNarrower,
+	"is related to":  SKOSRelated,
+	"are related to": SKOSRelated,
+}
+
+// WithSKOS exports a skos vocabulary rather than the propositions themselves.
+// Each concept map becomes a skos:ConceptScheme of skos:Concepts, labelled with
+// skos:prefLabel and skos:altLabel and defined by skos:definition. Propositions
+// whose predicates are in mapping, which is keyed by predicate and defaults to
+// DefaultSKOSMapping, become skos:broader, skos:narrower or skos:related
+// relations, and other propositions are left out. Concepts without a broader
+// concept are the top concepts of their scheme
+func WithSKOS(mapping map[string]SKOSRelation) ExporterOption {
+	return func(e *Exporter) {
+		if mapping == nil {
+			mapping = DefaultSKOSMapping
+		}
+
+		e.skos = map[string]SKOSRelation{}
+
+		for predicate, relation := range mapping {
+			e.skos[slug.Make(predicate)] = relation
+		}
+	}
+}
+
+// ParseSKOSRelation returns the SKOSRelation named s, and false if there isn't
+// one
+func ParseSKOSRelation(s string) (SKOSRelation, bool) {
+	switch r := SKOSRelation(strings.ToLower(strings.TrimSpace(s))); r {
+	case SKOSBroader, SKOSNarrower, SKOSRelated:
+		return r, true
+	}
+
+	return "", false
+}
+
+func (e *Exporter) skosGraph(maps []*conceptmap.ConceptMap) *graph {
+	g := &graph{
+		prefixes: []prefix{
+			{name: "rdf", iri: RDF},
+			{name: "rdfs", iri: RDFS},
+			{name: "skos", iri: SKOS},
+			{name: "concept", iri: e.concepts()},
+			{name: "map", iri: e.maps()},
+		},
+	}
+
+	for _, m := range maps {
+		scheme := e.maps() + m.Slug()
+
+		g.add(scheme, RDF+"type", iri(SKOS+"ConceptScheme"))
+		g.add(scheme, SKOS+"prefLabel", literal(m.Title))
+
+		if d := strings.TrimSpace(m.Description); d != "" {
+			g.add(scheme, RDFS+"comment", literal(d))
+		}
+
+		broader := map[string][]string{}
+		narrower := map[string][]string{}
+		related := map[string][]string{}
+
+		for _, p := range m.Propositions {
+			l, r := p.Left.Key(), p.Right.Key()
+
+			switch e.skos[p.Predicate.Slug()] {
+			case SKOSBroader:
+				broader[l] = append(broader[l], r)
+				narrower[r] = append(narrower[r], l)
+			case SKOSNarrower:
+				narrower[l] = append(narrower[l], r)
+				broader[r] = append(broader[r], l)
+			case SKOSRelated:
+				related[l] = append(related[l], r)
+				related[r] = append(related[r], l)
+			}
+		}
+
+		for _, c := range m.Concepts {
+			conceptIRI := e.concepts() + c.Key()
+
+			g.add(conceptIRI, RDF+"type", iri(SKOS+"Concept"))
+			g.add(conceptIRI, SKOS+"inScheme", iri(scheme))
+			g.add(conceptIRI, SKOS+"prefLabel", literal(c.Label))
+
+			for _, a := range c.Aliases {
+				g.add(conceptIRI, SKOS+"altLabel", literal(a))
+			}
+
+			if d := strings.TrimSpace(c.Description); d != "" {
+				g.add(conceptIRI, SKOS+"definition", literal(d))
+			}
+
+			if len(broader[c.Key()]) == 0 {
+				g.add(conceptIRI, SKOS+"topConceptOf", iri(scheme))
+				g.add(scheme, SKOS+"hasTopConcept", iri(conceptIRI))
+			}
+
+			for _, k := range broader[c.Key()] {
+				g.add(conceptIRI, SKOS+"broader", iri(e.concepts()+k))
+			}
+
+			for _, k := range narrower[c.Key()] {
+				g.add(conceptIRI, SKOS+"narrower", iri(e.concepts()+k))
+			}
+
+			for _, k := range related[c.Key()] {
+				g.add(conceptIRI, SKOS+"related", iri(e.concepts()+k))
+			}
+		}
+	}
+
+	return g.distinct()
+}
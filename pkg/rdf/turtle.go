@@ -0,0 +1,79 @@
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var turtleEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`)
+
+// writeTurtle writes g in turtle format, grouping the triples by subject
+func writeTurtle(w io.Writer, g *graph) error {
+	bw := bufio.NewWriter(w)
+
+	for _, p := range g.prefixes {
+		fmt.Fprintf(bw, "@prefix %s: <%s> .\n", p.name, p.iri)
+	}
+
+	for _, s := range g.subjects() {
+		fmt.Fprintf(bw, "\n%s", turtleIRI(g, s))
+
+		triples := g.about(s)
+
+		for i, t := range triples {
+			switch {
+			case i == 0:
+				fmt.Fprintf(bw, " %s %s", turtlePredicate(g, t.predicate), turtleTerm(g, t.object))
+			case t.predicate == triples[i-1].predicate:
+				fmt.Fprintf(bw, ",\n        %s", turtleTerm(g, t.object))
+			default:
+				fmt.Fprintf(bw, " ;\n    %s %s", turtlePredicate(g, t.predicate), turtleTerm(g, t.object))
+			}
+		}
+
+		bw.WriteString(" .\n")
+	}
+
+	return bw.Flush()
+}
+
+func turtleIRI(g *graph, s string) string {
+	if c, ok := g.compact(s); ok {
+		return c
+	}
+
+	return "<" + s + ">"
+}
+
+func turtlePredicate(g *graph, s string) string {
+	if s == RDF+"type" {
+		return "a"
+	}
+
+	return turtleIRI(g, s)
+}
+
+func turtleTerm(g *graph, t term) string {
+	if !t.literal {
+		return turtleIRI(g, t.iri)
+	}
+
+	if t.datatype == XSD+"boolean" {
+		return t.value
+	}
+
+	s := `"` + turtleEscaper.Replace(t.value) + `"`
+
+	if t.datatype != "" {
+		s += "^^" + turtleIRI(g, t.datatype)
+	}
+
+	return s
+}
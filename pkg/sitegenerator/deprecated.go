@@ -0,0 +1,46 @@
+package sitegenerator
+
+import (
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// NewIndexPageTemplate renders the built in index page for an mkdocs site.
+//
+// Deprecated: use Templates.IndexPage, which supports other site flavours and
+// overridden templates
+func NewIndexPageTemplate(conceptMaps []*conceptmap.ConceptMap) PageTemplate {
+	ph := NewFilePathHelper("")
+
+	return DefaultTemplates().IndexPage(ph.IndexMarkdownFile(), NewSiteIndex(conceptMaps), MkDocs.LinkHelper(0))
+}
+
+// NewConceptMapSummaryPageTemplate renders the built in summary page of a concept
+// map, linking to other pages with ph.
+//
+// Deprecated: use Templates.ConceptMapSummaryPage
+func NewConceptMapSummaryPageTemplate(conceptMap *conceptmap.ConceptMap, ph *FilePathHelper) PageTemplate {
+	page := NewFilePathHelper("").ConceptMapSummaryMarkdownFile(conceptMap)
+
+	return DefaultTemplates().ConceptMapSummaryPage(page, conceptMap, nil, ph)
+}
+
+// NewConceptMapDetailPageTemplate renders the built in detail page of a concept
+// map, linking to other pages with ph.
+//
+// Deprecated: use Templates.ConceptMapDetailPage
+func NewConceptMapDetailPageTemplate(conceptMap *conceptmap.ConceptMap, ph *FilePathHelper) PageTemplate {
+	page := NewFilePathHelper("").ConceptMapDetailMarkdownFile(conceptMap)
+
+	return DefaultTemplates().ConceptMapDetailPage(page, conceptMap, nil, ph)
+}
+
+// NewConceptPageTemplate renders the built in page of a concept, linking to other
+// pages with ph. Backlinks are only found within conceptMap.
+//
+// Deprecated: use Templates.ConceptPage, which finds backlinks across the site
+func NewConceptPageTemplate(conceptMap *conceptmap.ConceptMap, concept *conceptmap.Concept, ph *FilePathHelper) PageTemplate {
+	page := NewFilePathHelper("").ConceptMarkdownFile(conceptMap, concept)
+	site := NewSiteIndex([]*conceptmap.ConceptMap{conceptMap})
+
+	return DefaultTemplates().ConceptPage(page, conceptMap, concept, site, ph)
+}
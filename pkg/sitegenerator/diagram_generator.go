@@ -11,4 +11,8 @@ type DiagramGenerator interface {
 	GenerateConceptMapSummarySVG(ctx context.Context, cmap *conceptmap.ConceptMap, file string) error
 	GenerateConceptMapDetailSVG(ctx context.Context, cmap *conceptmap.ConceptMap, file string) error
 	GenerateSingleConceptSVG(ctx context.Context, cmap *conceptmap.ConceptMap, concept *conceptmap.Concept, file string) error
+	GenerateConceptMapPartSVG(ctx context.Context, cmap *conceptmap.ConceptMap, concepts []*conceptmap.Concept, file string) error
+	GenerateConceptMapOverviewSVG(ctx context.Context, cmap *conceptmap.ConceptMap, groups map[string]string, links map[string]string, file string) error
+	GenerateTagSVG(ctx context.Context, cmap *conceptmap.ConceptMap, tag string, file string) error
+	GeneratePathSVG(ctx context.Context, cmap *conceptmap.ConceptMap, from *conceptmap.Concept, to *conceptmap.Concept, paths []conceptmap.PropositionList, file string) error
 }
@@ -0,0 +1,43 @@
+/*
+Package sitegenerator generates markdown sites from concept maps.
+
+# Templates
+
+Every page is rendered by a text/template. The built in templates can be
+overridden by passing a directory to LoadTemplates, and the result to
+WithTemplates. Each *.tmpl file in the directory whose name matches a page
+template replaces it:
+
+	index.md.tmpl       the site's index page, rendered with IndexPageData
+	summary.md.tmpl     a concept map's summary page, rendered with ConceptMapPageData
+	detail.md.tmpl      a concept map's detail page, rendered with ConceptMapPageData
+	part.md.tmpl        one part of a concept map too large for a single diagram,
+	                    rendered with ConceptMapPartPageData
+	concept.md.tmpl     a single concept's page, rendered with ConceptPageData
+	glossary.md.tmpl    the site's glossary page, rendered with GlossaryPageData
+	predicates.md.tmpl  overview of every predicate, rendered with PredicatesPageData
+	predicate.md.tmpl   a single predicate's page, rendered with PredicatePageData
+	tags.md.tmpl        overview of every concept tag, rendered with TagsPageData
+	tag.md.tmpl         a single tag's page, rendered with TagPageData
+
+Any other *.tmpl file is added as a partial, and can be included from any
+template with {{ template "partial-name.tmpl" . }}.
+
+All templates can use the functions in TemplateFuncs:
+
+	relLink from to          path of to relative to the directory of from
+	slugify s                s as a url friendly slug
+	escape s                 s with markdown syntax escaped, for headings and
+	                         link text
+	markdown paths cmap d n  description d rendered beneath a heading of level
+	                         n, with its headings demoted below it and [[Label]]
+	                         references linked to concepts in cmap using the
+	                         FilePathHelper paths
+	propositionsFor cmap c   propositions in cmap involving concept c
+
+Links to other pages and images should be built with the Paths field of the
+page data, which follows the link conventions of the site's SiteFlavour, e.g.
+
+	[{{ .Concept.Label }}]({{ .Paths.ConceptMarkdownFile .ConceptMap .Concept }})
+*/
+package sitegenerator
@@ -5,10 +5,23 @@ import (
 	"path/filepath"
 
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/gosimple/slug"
 )
 
 type FilePathHelper struct {
+	// BaseDir is prepended to the paths of markdown pages
 	BaseDir string
+
+	// StaticDir is prepended to the paths of images. When empty, images are placed
+	// alongside the markdown pages under BaseDir
+	StaticDir string
+
+	// IndexPage is the file name of the site's index page. Defaults to index.md
+	IndexPage string
+
+	// PageLinkFunc, when set, converts the path of a markdown page into the form
+	// that the target site generator expects in links
+	PageLinkFunc func(string) string
 }
 
 func NewFilePathHelper(baseDir string) *FilePathHelper {
@@ -18,47 +31,118 @@ func NewFilePathHelper(baseDir string) *FilePathHelper {
 }
 
 func (h *FilePathHelper) ConceptMapSummaryImageFile(conceptMap *conceptmap.ConceptMap) string {
-	return filepath.Join(
-		h.BaseDir,
+	return h.static(
 		conceptMap.Slug(),
 		"images",
 		fmt.Sprintf("%s-summary.svg", conceptMap.Slug()))
 }
 
 func (h *FilePathHelper) ConceptMapDetailImageFile(conceptMap *conceptmap.ConceptMap) string {
-	return filepath.Join(
-		h.BaseDir,
+	return h.static(
 		conceptMap.Slug(),
 		"images",
 		fmt.Sprintf("%s-detail.svg", conceptMap.Slug()))
 }
 
+func (h *FilePathHelper) ConceptMapOverviewImageFile(conceptMap *conceptmap.ConceptMap) string {
+	return h.static(
+		conceptMap.Slug(),
+		"images",
+		fmt.Sprintf("%s-overview.svg", conceptMap.Slug()))
+}
+
+func (h *FilePathHelper) ConceptMapPartImageFile(conceptMap *conceptmap.ConceptMap, part *ConceptMapPart) string {
+	return h.static(
+		conceptMap.Slug(),
+		"images",
+		fmt.Sprintf("%s-part-%s.svg", conceptMap.Slug(), part.Slug()))
+}
+
+func (h *FilePathHelper) ConceptMapPartMarkdownFile(conceptMap *conceptmap.ConceptMap, part *ConceptMapPart) string {
+	return h.page(fmt.Sprintf("%s/parts/%s.md", conceptMap.Slug(), part.Slug()))
+}
+
 func (h *FilePathHelper) IndexMarkdownFile() string {
-	return filepath.Join(h.BaseDir, "index.md")
+	return h.page(h.indexPage())
+}
+
+func (h *FilePathHelper) GlossaryMarkdownFile() string {
+	return h.page("glossary.md")
+}
+
+func (h *FilePathHelper) PredicatesMarkdownFile() string {
+	return h.page("predicates", h.indexPage())
+}
+
+func (h *FilePathHelper) PredicateMarkdownFile(predicate conceptmap.Predicate) string {
+	return h.page(fmt.Sprintf("predicates/%s.md", predicate.Slug()))
+}
+
+// TagsMarkdownFile is placed in concept-tags/, rather than tags/, so that it
+// doesn't clash with the taxonomy pages that hugo and docusaurus generate for
+// front matter tags
+func (h *FilePathHelper) TagsMarkdownFile() string {
+	return h.page("concept-tags", h.indexPage())
+}
+
+func (h *FilePathHelper) TagMarkdownFile(tag string) string {
+	return h.page(fmt.Sprintf("concept-tags/%s.md", slug.Make(tag)))
+}
+
+func (h *FilePathHelper) TagImageFile(conceptMap *conceptmap.ConceptMap, tag string) string {
+	return h.static(
+		conceptMap.Slug(),
+		"images",
+		fmt.Sprintf("tag-%s.svg", slug.Make(tag)))
 }
 
 func (h *FilePathHelper) ConceptMapSummaryMarkdownFile(conceptMap *conceptmap.ConceptMap) string {
-	return filepath.Join(
-		h.BaseDir,
-		fmt.Sprintf("%s/summary.md", conceptMap.Slug()))
+	return h.page(fmt.Sprintf("%s/summary.md", conceptMap.Slug()))
 }
 
 func (h *FilePathHelper) ConceptMapDetailMarkdownFile(conceptMap *conceptmap.ConceptMap) string {
-	return filepath.Join(
-		h.BaseDir,
-		fmt.Sprintf("%s/detail.md", conceptMap.Slug()))
+	return h.page(fmt.Sprintf("%s/detail.md", conceptMap.Slug()))
 }
 
 func (h *FilePathHelper) ConceptImageFile(conceptMap *conceptmap.ConceptMap, concept *conceptmap.Concept) string {
-	return filepath.Join(
-		h.BaseDir,
+	return h.static(
 		conceptMap.Slug(),
 		"images",
 		fmt.Sprintf("%s.svg", concept.Key()))
 }
 
 func (h *FilePathHelper) ConceptMarkdownFile(conceptMap *conceptmap.ConceptMap, concept *conceptmap.Concept) string {
-	return filepath.Join(
-		h.BaseDir,
-		fmt.Sprintf("%s/concepts/%s.md", conceptMap.Slug(), concept.Key()))
+	return h.page(fmt.Sprintf("%s/concepts/%s.md", conceptMap.Slug(), concept.Key()))
+}
+
+func (h *FilePathHelper) SearchIndexFile() string {
+	return h.static("search-index.json")
+}
+
+func (h *FilePathHelper) indexPage() string {
+	if h.IndexPage == "" {
+		return "index.md"
+	}
+	return h.IndexPage
+}
+
+func (h *FilePathHelper) page(elem ...string) string {
+	p := filepath.Join(append([]string{h.BaseDir}, elem...)...)
+
+	if h.PageLinkFunc != nil {
+		return h.PageLinkFunc(p)
+	}
+
+	return p
+}
+
+func (h *FilePathHelper) static(elem ...string) string {
+	return filepath.Join(append([]string{h.staticDir()}, elem...)...)
+}
+
+func (h *FilePathHelper) staticDir() string {
+	if h.StaticDir == "" {
+		return h.BaseDir
+	}
+	return h.StaticDir
 }
@@ -0,0 +1,80 @@
+package sitegenerator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteFlavour adapts the generated markdown site to the directory layout, link
+// conventions and front matter expected by a particular static site generator
+type SiteFlavour interface {
+	// FilePathHelper returns the helper used to decide where generated files are
+	// written beneath outputDir
+	FilePathHelper(outputDir string) *FilePathHelper
+
+	// LinkHelper returns the helper used to build links from a page that lives
+	// depth directories below the root of the site's content
+	LinkHelper(depth int) *FilePathHelper
+
+	// PageURL returns the url that the generated page at contentPath, relative to
+	// the content dir, will be served from. The url is an absolute path from the
+	// root of the site, starting with a slash, and pages that are served as
+	// directories end with a slash
+	PageURL(contentPath string) string
+
+	// StaticURL returns the url that the generated image or other static file at
+	// staticPath, relative to the static dir, is served from. Like PageURL, the url
+	// is an absolute path from the root of the site
+	StaticURL(staticPath string) string
+
+	// Page wraps tpl with any front matter required by the flavour
+	Page(meta PageMeta, tpl PageTemplate) PageTemplate
+
+	// SectionFiles returns any additional files, keyed by file path, that describe
+	// the directory holding a concept map's pages
+	SectionFiles(cmap *conceptmap.ConceptMap, meta PageMeta, fph *FilePathHelper) map[string]PageTemplate
+}
+
+// PageMeta describes a generated page. It is used to build front matter
+type PageMeta struct {
+	Title      string
+	Weight     int
+	Tags       []string
+	Categories []string
+	Slug       string
+}
+
+// frontMatterPageTemplate renders fm as a yaml front matter block, followed by tpl
+func frontMatterPageTemplate(fm interface{}, tpl PageTemplate) PageTemplate {
+	return PageTemplateFunc(func(w io.Writer) error {
+		b, err := yaml.Marshal(fm)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "---\n%s---\n", b); err != nil {
+			return err
+		}
+
+		return tpl.Render(w)
+	})
+}
+
+// trimIndexPage removes the file name of an index page, such as index or _index,
+// from a content path without its extension. Only a whole final path segment is
+// removed, so that pages such as search-index are left alone
+func trimIndexPage(p string, name string) string {
+	if p == name {
+		return ""
+	}
+
+	if strings.HasSuffix(p, "/"+name) {
+		return strings.TrimSuffix(p, name)
+	}
+
+	return p
+}
@@ -0,0 +1,77 @@
+package sitegenerator
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// Docusaurus writes pages to docs/ and images to static/img/, links pages by their
+// relative markdown file path and adds yaml front matter to every page
+var Docusaurus SiteFlavour = &docusaurusFlavour{}
+
+type docusaurusFlavour struct{}
+
+type docusaurusFrontMatter struct {
+	Title           string   `yaml:"title"`
+	SidebarPosition int      `yaml:"sidebar_position,omitempty"`
+	Tags            []string `yaml:"tags,omitempty"`
+	Slug            string   `yaml:"slug,omitempty"`
+}
+
+type docusaurusCategory struct {
+	Label    string `json:"label"`
+	Position int    `json:"position,omitempty"`
+}
+
+func (f *docusaurusFlavour) FilePathHelper(outputDir string) *FilePathHelper {
+	return &FilePathHelper{
+		BaseDir:   filepath.Join(outputDir, "docs"),
+		StaticDir: filepath.Join(outputDir, "static", "img"),
+	}
+}
+
+// LinkHelper links pages relative to the markdown file, which docusaurus resolves
+// at build time. Files in static/ are served from the root of the site
+func (f *docusaurusFlavour) LinkHelper(depth int) *FilePathHelper {
+	return &FilePathHelper{
+		BaseDir:   strings.Repeat("../", depth),
+		StaticDir: "/img",
+	}
+}
+
+func (f *docusaurusFlavour) PageURL(contentPath string) string {
+	return "/docs/" + trimIndexPage(strings.TrimSuffix(contentPath, ".md"), "index")
+}
+
+func (f *docusaurusFlavour) StaticURL(staticPath string) string {
+	return "/img/" + staticPath
+}
+
+func (f *docusaurusFlavour) Page(meta PageMeta, tpl PageTemplate) PageTemplate {
+	return frontMatterPageTemplate(&docusaurusFrontMatter{
+		Title:           meta.Title,
+		SidebarPosition: meta.Weight,
+		Tags:            meta.Tags,
+		Slug:            meta.Slug,
+	}, tpl)
+}
+
+// SectionFiles adds a _category_.json to each concept map's directory so that the
+// sidebar shows the map's title in the right position
+func (f *docusaurusFlavour) SectionFiles(cmap *conceptmap.ConceptMap, meta PageMeta, fph *FilePathHelper) map[string]PageTemplate {
+	return map[string]PageTemplate{
+		filepath.Join(fph.BaseDir, cmap.Slug(), "_category_.json"): PageTemplateFunc(func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+
+			return enc.Encode(&docusaurusCategory{
+				Label:    meta.Title,
+				Position: meta.Weight,
+			})
+		}),
+	}
+}
@@ -0,0 +1,80 @@
+package sitegenerator
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// Hugo writes pages to content/ and images to static/, links pages using the relref
+// shortcode and adds yaml front matter to every page
+var Hugo SiteFlavour = &hugoFlavour{}
+
+type hugoFlavour struct{}
+
+type hugoFrontMatter struct {
+	Title      string   `yaml:"title"`
+	Weight     int      `yaml:"weight,omitempty"`
+	Tags       []string `yaml:"tags,omitempty"`
+	Categories []string `yaml:"categories,omitempty"`
+	Slug       string   `yaml:"slug,omitempty"`
+}
+
+func (f *hugoFlavour) FilePathHelper(outputDir string) *FilePathHelper {
+	return &FilePathHelper{
+		BaseDir:   filepath.Join(outputDir, "content"),
+		StaticDir: filepath.Join(outputDir, "static"),
+		IndexPage: "_index.md",
+	}
+}
+
+// LinkHelper ignores depth, as relref resolves paths from the content root and
+// everything in static/ is served from the root of the site
+func (f *hugoFlavour) LinkHelper(depth int) *FilePathHelper {
+	return &FilePathHelper{
+		BaseDir:   "/",
+		StaticDir: "/",
+		IndexPage: "_index.md",
+		PageLinkFunc: func(p string) string {
+			return fmt.Sprintf(`{{< relref "%s" >}}`, filepath.ToSlash(p))
+		},
+	}
+}
+
+func (f *hugoFlavour) PageURL(contentPath string) string {
+	p := strings.Trim(trimIndexPage(strings.TrimSuffix(contentPath, ".md"), "_index"), "/")
+
+	if p == "" {
+		return "/"
+	}
+
+	return "/" + p + "/"
+}
+
+func (f *hugoFlavour) StaticURL(staticPath string) string {
+	return "/" + staticPath
+}
+
+func (f *hugoFlavour) Page(meta PageMeta, tpl PageTemplate) PageTemplate {
+	return frontMatterPageTemplate(&hugoFrontMatter{
+		Title:      meta.Title,
+		Weight:     meta.Weight,
+		Tags:       meta.Tags,
+		Categories: meta.Categories,
+		Slug:       meta.Slug,
+	}, tpl)
+}
+
+// SectionFiles adds an _index.md to each concept map's directory so that hugo
+// treats it as a titled, ordered section
+func (f *hugoFlavour) SectionFiles(cmap *conceptmap.ConceptMap, meta PageMeta, fph *FilePathHelper) map[string]PageTemplate {
+	return map[string]PageTemplate{
+		filepath.Join(fph.BaseDir, cmap.Slug(), "_index.md"): f.Page(meta, PageTemplateFunc(func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, cmap.Description)
+			return err
+		})),
+	}
+}
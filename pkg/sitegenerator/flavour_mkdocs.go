@@ -0,0 +1,50 @@
+package sitegenerator
+
+import (
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// MkDocs generates plain markdown, with images alongside pages, suitable for mkdocs
+var MkDocs SiteFlavour = &mkdocsFlavour{}
+
+type mkdocsFlavour struct{}
+
+func (f *mkdocsFlavour) FilePathHelper(outputDir string) *FilePathHelper {
+	return NewFilePathHelper(outputDir)
+}
+
+// LinkHelper links pages relative to the markdown file, which mkdocs resolves at
+// build time. Images are linked relative to the page's directory url, which is one
+// level deeper than the markdown file
+func (f *mkdocsFlavour) LinkHelper(depth int) *FilePathHelper {
+	return &FilePathHelper{
+		BaseDir:   strings.Repeat("../", depth),
+		StaticDir: strings.Repeat("../", depth+1),
+	}
+}
+
+// PageURL assumes mkdocs' default use_directory_urls setting, and that the site
+// is served from the root of its domain
+func (f *mkdocsFlavour) PageURL(contentPath string) string {
+	p := trimIndexPage(strings.TrimSuffix(contentPath, ".md"), "index")
+
+	if p == "" || strings.HasSuffix(p, "/") {
+		return "/" + p
+	}
+
+	return "/" + p + "/"
+}
+
+func (f *mkdocsFlavour) StaticURL(staticPath string) string {
+	return "/" + staticPath
+}
+
+func (f *mkdocsFlavour) Page(meta PageMeta, tpl PageTemplate) PageTemplate {
+	return tpl
+}
+
+func (f *mkdocsFlavour) SectionFiles(cmap *conceptmap.ConceptMap, meta PageMeta, fph *FilePathHelper) map[string]PageTemplate {
+	return nil
+}
@@ -0,0 +1,38 @@
+package sitegenerator
+
+import "testing"
+
+func TestPageURL(t *testing.T) {
+	tests := []struct {
+		flavour     SiteFlavour
+		contentPath string
+		want        string
+	}{
+		{MkDocs, "index.md", "/"},
+		{MkDocs, "kubernetes/summary.md", "/kubernetes/summary/"},
+		{MkDocs, "kubernetes/index.md", "/kubernetes/"},
+		{MkDocs, "kubernetes/concepts/search-index.md", "/kubernetes/concepts/search-index/"},
+		{Docusaurus, "index.md", "/docs/"},
+		{Docusaurus, "kubernetes/summary.md", "/docs/kubernetes/summary"},
+		{Docusaurus, "kubernetes/index.md", "/docs/kubernetes/"},
+		{Docusaurus, "kubernetes/concepts/search-index.md", "/docs/kubernetes/concepts/search-index"},
+		{Hugo, "_index.md", "/"},
+		{Hugo, "kubernetes/summary.md", "/kubernetes/summary/"},
+		{Hugo, "kubernetes/_index.md", "/kubernetes/"},
+		{Hugo, "kubernetes/concepts/search_index.md", "/kubernetes/concepts/search_index/"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.flavour.PageURL(tt.contentPath); got != tt.want {
+			t.Errorf("%T.PageURL(%q) = %q, want %q", tt.flavour, tt.contentPath, got, tt.want)
+		}
+	}
+}
+
+func TestStaticURL(t *testing.T) {
+	for _, f := range []SiteFlavour{MkDocs, Docusaurus, Hugo} {
+		if got := f.StaticURL("kubernetes/images/kubernetes-summary.svg"); got[0] != '/' {
+			t.Errorf("%T.StaticURL returned %q, which isn't an absolute path", f, got)
+		}
+	}
+}
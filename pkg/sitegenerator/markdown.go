@@ -0,0 +1,124 @@
+package sitegenerator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"`", "\\`",
+		`*`, `\*`,
+		`_`, `\_`,
+		`[`, `\[`,
+		`]`, `\]`,
+		`<`, `\<`,
+		`>`, `\>`,
+		`#`, `\#`,
+		`|`, `\|`,
+		`!`, `\!`)
+
+	atxHeadingPattern    = regexp.MustCompile(`^ {0,3}(#{1,6})(\s.*|$)`)
+	setextHeadingPattern = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	codeFencePattern     = regexp.MustCompile("^ {0,3}(```|~~~)")
+)
+
+// escapeMarkdown escapes s so that it is rendered literally when used as inline
+// text, such as a heading or the text of a link
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// renderDescription prepares a markdown description for embedding in a page below
+// a heading of the given level. Headings in the description are demoted to nest
+// beneath it, and concept references such as [[Label]] are replaced with links to
+// the concept's page in cmap
+func renderDescription(ph *FilePathHelper, cmap *conceptmap.ConceptMap, description string, level int) string {
+	lines := strings.Split(strings.ReplaceAll(description, "\r\n", "\n"), "\n")
+	fenced := false
+
+	// Convert setext headings to atx headings, so that we only have one kind of
+	// heading to demote
+	for i := 1; i < len(lines); i++ {
+		if codeFencePattern.MatchString(lines[i-1]) {
+			fenced = !fenced
+		}
+
+		if fenced || !setextHeadingPattern.MatchString(lines[i]) || !isParagraphLine(lines[i-1]) {
+			continue
+		}
+
+		prefix := "## "
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "=") {
+			prefix = "# "
+		}
+
+		lines[i-1] = prefix + strings.TrimSpace(lines[i-1])
+		lines = append(lines[:i], lines[i+1:]...)
+	}
+
+	shift := 0
+	minLevel := 0
+	fenced = false
+
+	for _, line := range lines {
+		if codeFencePattern.MatchString(line) {
+			fenced = !fenced
+		}
+
+		if m := atxHeadingPattern.FindStringSubmatch(line); !fenced && m != nil {
+			if minLevel == 0 || len(m[1]) < minLevel {
+				minLevel = len(m[1])
+			}
+		}
+	}
+
+	if minLevel > 0 && minLevel <= level {
+		shift = level + 1 - minLevel
+	}
+
+	fenced = false
+
+	for i, line := range lines {
+		if codeFencePattern.MatchString(line) {
+			fenced = !fenced
+			continue
+		}
+
+		if fenced {
+			continue
+		}
+
+		if m := atxHeadingPattern.FindStringSubmatch(line); m != nil && shift > 0 {
+			n := len(m[1]) + shift
+			if n > 6 {
+				n = 6
+			}
+			line = strings.Repeat("#", n) + m[2]
+		}
+
+		lines[i] = conceptmap.ReplaceConceptReferences(line, func(ref *conceptmap.ConceptReference) string {
+			if c := cmap.Concept(ref.Label); c != nil {
+				return fmt.Sprintf("[%s](%s)", escapeMarkdown(ref.Text), ph.ConceptMarkdownFile(cmap, c))
+			}
+			return escapeMarkdown(ref.Text)
+		})
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), " \t\n")
+}
+
+func isParagraphLine(s string) bool {
+	trimmed := strings.TrimSpace(s)
+
+	return trimmed != "" &&
+		!atxHeadingPattern.MatchString(s) &&
+		!codeFencePattern.MatchString(s) &&
+		!strings.HasPrefix(trimmed, "- ") &&
+		!strings.HasPrefix(trimmed, "* ") &&
+		!strings.HasPrefix(trimmed, ">")
+}
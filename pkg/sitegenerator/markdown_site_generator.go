@@ -4,10 +4,13 @@ import (
 	"context"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
 	"github.com/bernos/conceptmapper/pkg/diagrams"
+	"github.com/gosimple/slug"
 )
 
 type PageTemplate interface {
@@ -22,44 +25,103 @@ func (fn PageTemplateFunc) Render(w io.Writer) error {
 
 type MarkdownSiteGenerator struct {
 	diagramGenerator DiagramGenerator
+	flavour          SiteFlavour
+	templates        *Templates
 	filePathHelper   *FilePathHelper
+	maxDetailNodes   int
+	partitioner      diagrams.ConceptGrouper
 }
 
 func NewMarkdownSiteGenerator(outputDir string, opts ...SiteGeneratorOption) *MarkdownSiteGenerator {
 
 	sg := &MarkdownSiteGenerator{
 		diagramGenerator: diagrams.NewD2DiagramGenerator(),
-		filePathHelper:   NewFilePathHelper(outputDir),
+		flavour:          MkDocs,
+		templates:        DefaultTemplates(),
+		partitioner:      diagrams.GroupByKeyConcept(),
 	}
 
 	for _, o := range opts {
 		o(sg)
 	}
 
+	sg.filePathHelper = sg.flavour.FilePathHelper(outputDir)
+
 	return sg
 }
 
+// NewHugoSiteGenerator creates a MarkdownSiteGenerator that lays out its output as
+// a hugo site
+func NewHugoSiteGenerator(outputDir string, opts ...SiteGeneratorOption) *MarkdownSiteGenerator {
+	return NewMarkdownSiteGenerator(outputDir, append([]SiteGeneratorOption{WithSiteFlavour(Hugo)}, opts...)...)
+}
+
+// NewDocusaurusSiteGenerator creates a MarkdownSiteGenerator that lays out its
+// output as a docusaurus site
+func NewDocusaurusSiteGenerator(outputDir string, opts ...SiteGeneratorOption) *MarkdownSiteGenerator {
+	return NewMarkdownSiteGenerator(outputDir, append([]SiteGeneratorOption{WithSiteFlavour(Docusaurus)}, opts...)...)
+}
+
 func (sg *MarkdownSiteGenerator) GenerateSite(ctx context.Context, cmaps []*conceptmap.ConceptMap) error {
+	site := NewSiteIndex(cmaps)
+
+	indexFile := sg.filePathHelper.IndexMarkdownFile()
 
 	if err := sg.renderTemplateToFile(
-		sg.filePathHelper.IndexMarkdownFile(),
-		NewIndexPageTemplate(cmaps)); err != nil {
+		indexFile,
+		sg.flavour.Page(
+			PageMeta{Title: "Concept Maps", Weight: 1},
+			sg.templates.IndexPage(sg.contentPath(indexFile), site, sg.flavour.LinkHelper(0)))); err != nil {
+		return err
+	}
+
+	glossaryFile := sg.filePathHelper.GlossaryMarkdownFile()
+
+	if err := sg.renderTemplateToFile(
+		glossaryFile,
+		sg.flavour.Page(
+			PageMeta{Title: "Glossary", Weight: len(cmaps) + 2, Slug: "glossary"},
+			sg.templates.GlossaryPage(sg.contentPath(glossaryFile), site, sg.flavour.LinkHelper(0)))); err != nil {
+		return err
+	}
+
+	if err := sg.generatePredicatePages(site, len(cmaps)+3); err != nil {
+		return err
+	}
+
+	if err := sg.generateTagPages(ctx, site, len(cmaps)+4); err != nil {
 		return err
 	}
 
-	for _, cmap := range cmaps {
-		if err := sg.generateConceptMapSummaryPage(ctx, cmap); err != nil {
+	if err := sg.renderTemplateToFile(
+		sg.filePathHelper.SearchIndexFile(),
+		NewSearchIndexTemplate(cmaps, sg.filePathHelper, sg.pageURL)); err != nil {
+		return err
+	}
+
+	for i, cmap := range cmaps {
+		section := PageMeta{Title: cmap.Title, Weight: i + 1}
+
+		for file, tpl := range sg.flavour.SectionFiles(cmap, section, sg.filePathHelper) {
+			if err := sg.renderTemplateToFile(file, tpl); err != nil {
+				return err
+			}
+		}
+
+		parts := sg.conceptMapParts(cmap)
+
+		if err := sg.generateConceptMapSummaryPage(ctx, cmap, parts); err != nil {
 			return err
 		}
 
-		if cmap.HasKeyConcepts() {
-			if err := sg.generateConceptMapDetailPage(ctx, cmap); err != nil {
+		if cmap.HasKeyConcepts() || len(parts) > 0 {
+			if err := sg.generateConceptMapDetailPage(ctx, cmap, parts); err != nil {
 				return err
 			}
 		}
 
-		for _, concept := range cmap.Concepts {
-			if err := sg.generateConceptPage(ctx, cmap, concept); err != nil {
+		for j, concept := range cmap.Concepts {
+			if err := sg.generateConceptPage(ctx, site, cmap, concept, j+3); err != nil {
 				return err
 			}
 		}
@@ -68,40 +130,259 @@ func (sg *MarkdownSiteGenerator) GenerateSite(ctx context.Context, cmaps []*conc
 	return nil
 }
 
-func (sg *MarkdownSiteGenerator) generateConceptMapSummaryPage(ctx context.Context, cmap *conceptmap.ConceptMap) error {
+func (sg *MarkdownSiteGenerator) generatePredicatePages(site *SiteIndex, weight int) error {
+	file := sg.filePathHelper.PredicatesMarkdownFile()
+
+	if err := sg.renderTemplateToFile(
+		file,
+		sg.flavour.Page(
+			PageMeta{Title: "Predicates", Weight: weight},
+			sg.templates.PredicatesPage(sg.contentPath(file), site, sg.flavour.LinkHelper(1)))); err != nil {
+		return err
+	}
+
+	for i, usage := range site.Predicates() {
+		file := sg.filePathHelper.PredicateMarkdownFile(usage.Predicate)
+
+		meta := PageMeta{
+			Title:  string(usage.Predicate),
+			Weight: i + 1,
+			Slug:   usage.Predicate.Slug(),
+		}
+
+		if err := sg.renderTemplateToFile(
+			file,
+			sg.flavour.Page(meta, sg.templates.PredicatePage(sg.contentPath(file), usage, sg.flavour.LinkHelper(1)))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sg *MarkdownSiteGenerator) generateTagPages(ctx context.Context, site *SiteIndex, weight int) error {
+	if len(site.Tags()) == 0 {
+		return nil
+	}
+
+	file := sg.filePathHelper.TagsMarkdownFile()
+
+	if err := sg.renderTemplateToFile(
+		file,
+		sg.flavour.Page(
+			PageMeta{Title: "Tags", Weight: weight},
+			sg.templates.TagsPage(sg.contentPath(file), site, sg.flavour.LinkHelper(1)))); err != nil {
+		return err
+	}
+
+	for i, usage := range site.Tags() {
+		for _, cmap := range usage.ConceptMaps {
+			if err := sg.diagramGenerator.GenerateTagSVG(ctx, cmap, usage.Tag, sg.filePathHelper.TagImageFile(cmap, usage.Tag)); err != nil {
+				return err
+			}
+		}
+
+		file := sg.filePathHelper.TagMarkdownFile(usage.Tag)
+
+		meta := PageMeta{
+			Title:  usage.Tag,
+			Weight: i + 1,
+			Slug:   slug.Make(usage.Tag),
+		}
+
+		if err := sg.renderTemplateToFile(
+			file,
+			sg.flavour.Page(meta, sg.templates.TagPage(sg.contentPath(file), usage, sg.flavour.LinkHelper(1)))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// conceptMapParts splits cmap into parts if it has more concepts than can be drawn
+// in a single detail diagram, otherwise it returns nil
+func (sg *MarkdownSiteGenerator) conceptMapParts(cmap *conceptmap.ConceptMap) []*ConceptMapPart {
+	if sg.maxDetailNodes <= 0 || len(cmap.Concepts) <= sg.maxDetailNodes {
+		return nil
+	}
+
+	groups := map[string]string{}
+
+	if sg.partitioner != nil {
+		groups = sg.partitioner(cmap)
+	}
+
+	if len(groups) == 0 {
+		groups = diagrams.GroupByCommunity()(cmap)
+	}
+
+	// Parts that are still too large, including the whole map if it couldn't be
+	// partitioned, are split further
+	parts := splitOversizedParts(cmap, NewConceptMapParts(cmap, groups), sg.maxDetailNodes)
+
+	if len(parts) < 2 {
+		return nil
+	}
+
+	return parts
+}
+
+func (sg *MarkdownSiteGenerator) generateConceptMapSummaryPage(ctx context.Context, cmap *conceptmap.ConceptMap, parts []*ConceptMapPart) error {
 	diagramFile := sg.filePathHelper.ConceptMapSummaryImageFile(cmap)
 
 	if err := sg.diagramGenerator.GenerateConceptMapSummarySVG(ctx, cmap, diagramFile); err != nil {
 		return err
 	}
 
+	meta := PageMeta{
+		Title:  cmap.Title,
+		Weight: 1,
+		Tags:   []string{cmap.Title},
+		Slug:   "summary",
+	}
+
+	file := sg.filePathHelper.ConceptMapSummaryMarkdownFile(cmap)
+
 	return sg.renderTemplateToFile(
-		sg.filePathHelper.ConceptMapSummaryMarkdownFile(cmap),
-		NewConceptMapSummaryPageTemplate(cmap, NewFilePathHelper("../../")))
+		file,
+		sg.flavour.Page(meta, sg.templates.ConceptMapSummaryPage(sg.contentPath(file), cmap, parts, sg.flavour.LinkHelper(1))))
 }
 
-func (sg *MarkdownSiteGenerator) generateConceptMapDetailPage(ctx context.Context, cmap *conceptmap.ConceptMap) error {
-	diagramFile := sg.filePathHelper.ConceptMapDetailImageFile(cmap)
+func (sg *MarkdownSiteGenerator) generateConceptMapDetailPage(ctx context.Context, cmap *conceptmap.ConceptMap, parts []*ConceptMapPart) error {
+	if len(parts) > 0 {
+		if err := sg.generateConceptMapPartPages(ctx, cmap, parts); err != nil {
+			return err
+		}
+	} else {
+		diagramFile := sg.filePathHelper.ConceptMapDetailImageFile(cmap)
 
-	if err := sg.diagramGenerator.GenerateConceptMapDetailSVG(ctx, cmap, diagramFile); err != nil {
-		return err
+		if err := sg.diagramGenerator.GenerateConceptMapDetailSVG(ctx, cmap, diagramFile); err != nil {
+			return err
+		}
+	}
+
+	meta := PageMeta{
+		Title:  cmap.Title + " (Detail)",
+		Weight: 2,
+		Tags:   []string{cmap.Title},
+		Slug:   "detail",
 	}
 
+	file := sg.filePathHelper.ConceptMapDetailMarkdownFile(cmap)
+
 	return sg.renderTemplateToFile(
-		sg.filePathHelper.ConceptMapDetailMarkdownFile(cmap),
-		NewConceptMapDetailPageTemplate(cmap, NewFilePathHelper("../../")))
+		file,
+		sg.flavour.Page(meta, sg.templates.ConceptMapDetailPage(sg.contentPath(file), cmap, parts, sg.flavour.LinkHelper(1))))
+}
+
+// generateConceptMapPartPages generates a page and diagram for each part, along
+// with an overview diagram that links each part to its page
+func (sg *MarkdownSiteGenerator) generateConceptMapPartPages(ctx context.Context, cmap *conceptmap.ConceptMap, parts []*ConceptMapPart) error {
+	overviewFile := sg.filePathHelper.ConceptMapOverviewImageFile(cmap)
+	groups := map[string]string{}
+	links := map[string]string{}
+
+	for i, part := range parts {
+		diagramFile := sg.filePathHelper.ConceptMapPartImageFile(cmap, part)
+
+		if err := sg.diagramGenerator.GenerateConceptMapPartSVG(ctx, cmap, part.Concepts, diagramFile); err != nil {
+			return err
+		}
+
+		file := sg.filePathHelper.ConceptMapPartMarkdownFile(cmap, part)
+
+		meta := PageMeta{
+			Title:  part.Name,
+			Weight: i + 1,
+			Tags:   []string{cmap.Title},
+			Slug:   part.Slug(),
+		}
+
+		if err := sg.renderTemplateToFile(
+			file,
+			sg.flavour.Page(meta, sg.templates.ConceptMapPartPage(sg.contentPath(file), cmap, part, sg.flavour.LinkHelper(2)))); err != nil {
+			return err
+		}
+
+		for _, c := range part.Concepts {
+			groups[c.Key()] = part.Name
+		}
+
+		links[part.Name] = sg.linkFromStaticFile(overviewFile, file)
+	}
+
+	return sg.diagramGenerator.GenerateConceptMapOverviewSVG(ctx, cmap, groups, links, overviewFile)
 }
 
-func (sg *MarkdownSiteGenerator) generateConceptPage(ctx context.Context, cmap *conceptmap.ConceptMap, concept *conceptmap.Concept) error {
+func (sg *MarkdownSiteGenerator) generateConceptPage(ctx context.Context, site *SiteIndex, cmap *conceptmap.ConceptMap, concept *conceptmap.Concept, weight int) error {
 	diagramFile := sg.filePathHelper.ConceptImageFile(cmap, concept)
 
 	if err := sg.diagramGenerator.GenerateSingleConceptSVG(ctx, cmap, concept, diagramFile); err != nil {
 		return err
 	}
 
+	meta := PageMeta{
+		Title:      concept.Label,
+		Weight:     weight,
+		Tags:       append([]string{cmap.Title}, concept.Tags...),
+		Categories: concept.Categories,
+		Slug:       concept.Key(),
+	}
+
+	if concept.IsKeyConcept {
+		meta.Tags = append(meta.Tags, "Key Concept")
+	}
+
+	file := sg.filePathHelper.ConceptMarkdownFile(cmap, concept)
+
 	return sg.renderTemplateToFile(
-		sg.filePathHelper.ConceptMarkdownFile(cmap, concept),
-		NewConceptPageTemplate(cmap, concept, NewFilePathHelper("../../../")))
+		file,
+		sg.flavour.Page(meta, sg.templates.ConceptPage(sg.contentPath(file), cmap, concept, site, sg.flavour.LinkHelper(2))))
+}
+
+// contentPath returns the path of file relative to the root of the site's content
+func (sg *MarkdownSiteGenerator) contentPath(file string) string {
+	p, err := filepath.Rel(sg.filePathHelper.BaseDir, file)
+	if err != nil {
+		return file
+	}
+
+	return filepath.ToSlash(p)
+}
+
+// pageURL returns the url that the generated page at file will be served from
+func (sg *MarkdownSiteGenerator) pageURL(file string) string {
+	return sg.flavour.PageURL(sg.contentPath(file))
+}
+
+// staticURL returns the url that the generated static file at file will be
+// served from
+func (sg *MarkdownSiteGenerator) staticURL(file string) string {
+	p, err := filepath.Rel(sg.filePathHelper.staticDir(), file)
+	if err != nil {
+		p = file
+	}
+
+	return sg.flavour.StaticURL(filepath.ToSlash(p))
+}
+
+// linkFromStaticFile returns a link to the generated page at file that can be
+// used from within the generated static file at staticFile, such as an svg
+func (sg *MarkdownSiteGenerator) linkFromStaticFile(staticFile string, file string) string {
+	from := path.Dir(sg.staticURL(staticFile))
+	to := sg.pageURL(file)
+
+	link, err := filepath.Rel(from, to)
+	if err != nil {
+		return to
+	}
+
+	if strings.HasSuffix(to, "/") {
+		link += "/"
+	}
+
+	return filepath.ToSlash(link)
 }
 
 func (sg *MarkdownSiteGenerator) renderTemplateToFile(file string, tpl PageTemplate) error {
@@ -114,5 +395,7 @@ func (sg *MarkdownSiteGenerator) renderTemplateToFile(file string, tpl PageTempl
 		return err
 	}
 
+	defer f.Close()
+
 	return tpl.Render(f)
 }
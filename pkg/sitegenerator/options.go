@@ -1,5 +1,7 @@
 package sitegenerator
 
+import "github.com/bernos/conceptmapper/pkg/diagrams"
+
 type SiteGeneratorOption func(*MarkdownSiteGenerator)
 
 func WithDiagramGenerator(dg DiagramGenerator) SiteGeneratorOption {
@@ -7,3 +9,26 @@ func WithDiagramGenerator(dg DiagramGenerator) SiteGeneratorOption {
 		sg.diagramGenerator = dg
 	}
 }
+
+// WithMaxDetailNodes splits the detail diagram of any concept map with more than
+// n concepts into parts, using partitioner to assign concepts to parts. Each part
+// gets its own page, and the detail page shows an overview of the parts. If
+// partitioner doesn't assign any concepts, the map is split into communities
+func WithMaxDetailNodes(n int, partitioner diagrams.ConceptGrouper) SiteGeneratorOption {
+	return func(sg *MarkdownSiteGenerator) {
+		sg.maxDetailNodes = n
+		sg.partitioner = partitioner
+	}
+}
+
+func WithSiteFlavour(f SiteFlavour) SiteGeneratorOption {
+	return func(sg *MarkdownSiteGenerator) {
+		sg.flavour = f
+	}
+}
+
+func WithTemplates(t *Templates) SiteGeneratorOption {
+	return func(sg *MarkdownSiteGenerator) {
+		sg.templates = t
+	}
+}
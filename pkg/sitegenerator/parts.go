@@ -0,0 +1,118 @@
+package sitegenerator
+
+import (
+	"fmt"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/bernos/conceptmapper/pkg/diagrams"
+	"github.com/gosimple/slug"
+)
+
+// ConceptMapPart is a named subset of the concepts in a concept map that is too
+// large to draw in a single detail diagram. Each part has its own page
+type ConceptMapPart struct {
+	Name     string
+	Concepts []*conceptmap.Concept
+}
+
+// Slug is the slugified version of Part.Name
+func (p *ConceptMapPart) Slug() string {
+	return slug.Make(p.Name)
+}
+
+// NewConceptMapParts splits the concepts in cmap into parts according to groups,
+// which maps concept keys to group names. Parts are ordered by the first
+// appearance of one of their concepts in the map. Concepts without a group are
+// placed in a part named Other
+func NewConceptMapParts(cmap *conceptmap.ConceptMap, groups map[string]string) []*ConceptMapPart {
+	parts := []*ConceptMapPart{}
+	index := map[string]*ConceptMapPart{}
+
+	for _, c := range cmap.Concepts {
+		name := groups[c.Key()]
+		if name == "" {
+			name = "Other"
+		}
+
+		part, ok := index[slug.Make(name)]
+		if !ok {
+			part = &ConceptMapPart{Name: name}
+			index[slug.Make(name)] = part
+			parts = append(parts, part)
+		}
+
+		part.Concepts = append(part.Concepts, c)
+	}
+
+	return parts
+}
+
+// splitOversizedParts splits each of parts with more than max concepts into
+// communities of the concepts within it, or into consecutive runs of at most max
+// concepts if it can't be split into communities, until every part fits in a
+// single detail diagram. Parts are renamed where needed so that their names are
+// unique
+func splitOversizedParts(cmap *conceptmap.ConceptMap, parts []*ConceptMapPart, max int) []*ConceptMapPart {
+	output := []*ConceptMapPart{}
+
+	for _, part := range parts {
+		output = append(output, splitOversizedPart(cmap, part, max)...)
+	}
+
+	return uniquePartNames(output)
+}
+
+func splitOversizedPart(cmap *conceptmap.ConceptMap, part *ConceptMapPart, max int) []*ConceptMapPart {
+	if len(part.Concepts) <= max {
+		return []*ConceptMapPart{part}
+	}
+
+	sub := &conceptmap.ConceptMap{
+		Concepts:     part.Concepts,
+		Propositions: cmap.Propositions.ConnectingConcepts(part.Concepts...),
+	}
+
+	if subparts := NewConceptMapParts(sub, diagrams.GroupByCommunity()(sub)); len(subparts) > 1 {
+		output := []*ConceptMapPart{}
+
+		for _, p := range subparts {
+			output = append(output, splitOversizedPart(cmap, p, max)...)
+		}
+
+		return output
+	}
+
+	output := []*ConceptMapPart{}
+
+	for i := 0; i < len(part.Concepts); i += max {
+		end := i + max
+		if end > len(part.Concepts) {
+			end = len(part.Concepts)
+		}
+
+		output = append(output, &ConceptMapPart{
+			Name:     fmt.Sprintf("%s %d", part.Name, i/max+1),
+			Concepts: part.Concepts[i:end],
+		})
+	}
+
+	return output
+}
+
+// uniquePartNames numbers parts whose names share a slug with an earlier part
+func uniquePartNames(parts []*ConceptMapPart) []*ConceptMapPart {
+	seen := map[string]bool{}
+
+	for _, part := range parts {
+		name := part.Name
+
+		for n := 2; seen[slug.Make(name)]; n++ {
+			name = fmt.Sprintf("%s %d", part.Name, n)
+		}
+
+		part.Name = name
+		seen[slug.Make(name)] = true
+	}
+
+	return parts
+}
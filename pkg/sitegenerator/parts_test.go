@@ -0,0 +1,95 @@
+package sitegenerator
+
+import (
+	"testing"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/gosimple/slug"
+)
+
+// linkedMap returns a map with propositions linking each pair of labels
+func linkedMap(labels ...string) *conceptmap.ConceptMap {
+	m := &conceptmap.ConceptMap{}
+	concepts := map[string]*conceptmap.Concept{}
+
+	concept := func(l string) *conceptmap.Concept {
+		if _, ok := concepts[l]; !ok {
+			concepts[l] = &conceptmap.Concept{Label: l}
+			m.Concepts = append(m.Concepts, concepts[l])
+		}
+		return concepts[l]
+	}
+
+	for i := 0; i+1 < len(labels); i += 2 {
+		m.Propositions = append(m.Propositions, &conceptmap.Proposition{
+			Left:      concept(labels[i]),
+			Predicate: "links to",
+			Right:     concept(labels[i+1]),
+		})
+	}
+
+	return m
+}
+
+func assertParts(t *testing.T, cmap *conceptmap.ConceptMap, parts []*ConceptMapPart, max int) {
+	t.Helper()
+
+	if len(parts) < 2 {
+		t.Fatalf("expected the map to be split, got %d parts", len(parts))
+	}
+
+	names := map[string]bool{}
+	count := 0
+
+	for _, p := range parts {
+		if len(p.Concepts) > max {
+			t.Errorf("part %q has %d concepts, more than %d", p.Name, len(p.Concepts), max)
+		}
+
+		if names[slug.Make(p.Name)] {
+			t.Errorf("part name %q is not unique", p.Name)
+		}
+
+		names[slug.Make(p.Name)] = true
+		count += len(p.Concepts)
+	}
+
+	if count != len(cmap.Concepts) {
+		t.Errorf("expected %d concepts across the parts, got %d", len(cmap.Concepts), count)
+	}
+}
+
+func TestConceptMapPartsSplitsAChain(t *testing.T) {
+	cmap := linkedMap("A", "B", "B", "C", "C", "D", "D", "E", "E", "F", "F", "G", "G", "H")
+	sg := NewMarkdownSiteGenerator("", WithMaxDetailNodes(3, nil))
+
+	assertParts(t, cmap, sg.conceptMapParts(cmap), 3)
+}
+
+func TestConceptMapPartsSplitsCliquesJoinedByABridge(t *testing.T) {
+	cmap := linkedMap(
+		"A", "B", "B", "C", "C", "A",
+		"D", "E", "E", "F", "F", "D",
+		"C", "D")
+	sg := NewMarkdownSiteGenerator("", WithMaxDetailNodes(3, nil))
+
+	parts := sg.conceptMapParts(cmap)
+
+	assertParts(t, cmap, parts, 3)
+
+	if len(parts) != 2 {
+		t.Errorf("expected a part for each clique, got %d parts", len(parts))
+	}
+}
+
+func TestConceptMapPartsSplitsBySizeWhenPartitioningFails(t *testing.T) {
+	// A single clique has no communities to split it into
+	cmap := linkedMap(
+		"A", "B", "A", "C", "A", "D", "A", "E",
+		"B", "C", "B", "D", "B", "E",
+		"C", "D", "C", "E",
+		"D", "E")
+	sg := NewMarkdownSiteGenerator("", WithMaxDetailNodes(2, nil))
+
+	assertParts(t, cmap, sg.conceptMapParts(cmap), 2)
+}
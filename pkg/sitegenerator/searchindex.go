@@ -0,0 +1,90 @@
+package sitegenerator
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+// SearchDocument is a single entry in the search index. Every searchable field is
+// a plain string, so the index can be passed as is to lunr's Builder.add or
+// MiniSearch's addAll
+type SearchDocument struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	Aliases      string `json:"aliases,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Propositions string `json:"propositions,omitempty"`
+	Map          string `json:"map"`
+	URL          string `json:"url"`
+}
+
+// Types of SearchDocument
+const (
+	SearchDocumentTypeConceptMap = "map"
+	SearchDocumentTypeConcept    = "concept"
+)
+
+// NewSearchIndex builds a search document for every concept map and concept in
+// cmaps. pageURL converts the path of a page, as returned by fph, to the url it is
+// served from
+func NewSearchIndex(cmaps []*conceptmap.ConceptMap, fph *FilePathHelper, pageURL func(string) string) []*SearchDocument {
+	docs := []*SearchDocument{}
+
+	for _, cmap := range cmaps {
+		docs = append(docs, &SearchDocument{
+			ID:           cmap.Slug(),
+			Type:         SearchDocumentTypeConceptMap,
+			Title:        cmap.Title,
+			Description:  plainDescription(cmap.Description),
+			Propositions: propositionSentences(cmap.Propositions),
+			Map:          cmap.Title,
+			URL:          pageURL(fph.ConceptMapSummaryMarkdownFile(cmap)),
+		})
+
+		for _, c := range cmap.Concepts {
+			docs = append(docs, &SearchDocument{
+				ID:           cmap.Slug() + "/" + c.Key(),
+				Type:         SearchDocumentTypeConcept,
+				Title:        c.Label,
+				Aliases:      strings.Join(c.Aliases, ", "),
+				Description:  plainDescription(c.Description),
+				Propositions: propositionSentences(cmap.Propositions.InvolvingConcepts(c)),
+				Map:          cmap.Title,
+				URL:          pageURL(fph.ConceptMarkdownFile(cmap, c)),
+			})
+		}
+	}
+
+	return docs
+}
+
+// NewSearchIndexTemplate renders the search index for cmaps as json
+func NewSearchIndexTemplate(cmaps []*conceptmap.ConceptMap, fph *FilePathHelper, pageURL func(string) string) PageTemplate {
+	return PageTemplateFunc(func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(NewSearchIndex(cmaps, fph, pageURL))
+	})
+}
+
+func propositionSentences(ps conceptmap.PropositionList) string {
+	sentences := make([]string, len(ps))
+
+	for i, p := range ps {
+		sentences[i] = p.String()
+	}
+
+	return strings.Join(sentences, "\n")
+}
+
+// plainDescription replaces concept references in s with their display text
+func plainDescription(s string) string {
+	return strings.TrimSpace(conceptmap.ReplaceConceptReferences(s, func(ref *conceptmap.ConceptReference) string {
+		return ref.Text
+	}))
+}
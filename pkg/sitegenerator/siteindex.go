@@ -0,0 +1,192 @@
+package sitegenerator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/gosimple/slug"
+)
+
+// ConceptInMap identifies a concept within a particular concept map
+type ConceptInMap struct {
+	ConceptMap *conceptmap.ConceptMap
+	Concept    *conceptmap.Concept
+}
+
+// PropositionInMap identifies a proposition within a particular concept map
+type PropositionInMap struct {
+	ConceptMap  *conceptmap.ConceptMap
+	Proposition *conceptmap.Proposition
+}
+
+// PredicateUsage describes every use of a predicate across the site
+type PredicateUsage struct {
+	Predicate    conceptmap.Predicate
+	ConceptMaps  []*conceptmap.ConceptMap
+	Propositions []*PropositionInMap
+}
+
+// Count is the number of propositions that use the predicate
+func (u *PredicateUsage) Count() int {
+	return len(u.Propositions)
+}
+
+// TagUsage describes every concept tagged with a tag across the site
+type TagUsage struct {
+	Tag         string
+	ConceptMaps []*conceptmap.ConceptMap
+	Concepts    []*ConceptInMap
+}
+
+// SiteIndex records how concepts are connected across every concept map in a
+// site. It is built once per site, so that templates don't need to search every
+// map for each page they render
+type SiteIndex struct {
+	conceptMaps   []*conceptmap.ConceptMap
+	occurrences   map[string][]*ConceptInMap
+	backlinks     map[string][]*ConceptInMap
+	mapReferences map[string][]*conceptmap.ConceptMap
+	predicates    []*PredicateUsage
+	tags          []*TagUsage
+}
+
+// NewSiteIndex indexes the concepts and concept references in cmaps. Concepts in
+// different maps with the same key are considered to be the same concept
+func NewSiteIndex(cmaps []*conceptmap.ConceptMap) *SiteIndex {
+	idx := &SiteIndex{
+		conceptMaps:   cmaps,
+		occurrences:   map[string][]*ConceptInMap{},
+		backlinks:     map[string][]*ConceptInMap{},
+		mapReferences: map[string][]*conceptmap.ConceptMap{},
+	}
+
+	predicates := map[string]*PredicateUsage{}
+	tags := map[string]*TagUsage{}
+
+	for _, cmap := range cmaps {
+		for _, p := range cmap.Propositions {
+			u, ok := predicates[p.Predicate.Slug()]
+			if !ok {
+				u = &PredicateUsage{Predicate: p.Predicate}
+				predicates[p.Predicate.Slug()] = u
+				idx.predicates = append(idx.predicates, u)
+			}
+
+			u.ConceptMaps = appendDistinctMap(u.ConceptMaps, cmap)
+			u.Propositions = append(u.Propositions, &PropositionInMap{ConceptMap: cmap, Proposition: p})
+		}
+
+		for _, ref := range conceptmap.FindConceptReferences(cmap.Description) {
+			key := referencedKey(cmap, ref)
+			idx.mapReferences[key] = appendDistinctMap(idx.mapReferences[key], cmap)
+		}
+
+		for _, c := range cmap.Concepts {
+			idx.occurrences[c.Key()] = append(idx.occurrences[c.Key()], &ConceptInMap{ConceptMap: cmap, Concept: c})
+
+			for _, t := range c.Tags {
+				u, ok := tags[slug.Make(t)]
+				if !ok {
+					u = &TagUsage{Tag: t}
+					tags[slug.Make(t)] = u
+					idx.tags = append(idx.tags, u)
+				}
+
+				u.ConceptMaps = appendDistinctMap(u.ConceptMaps, cmap)
+				u.Concepts = appendDistinctConcept(u.Concepts, &ConceptInMap{ConceptMap: cmap, Concept: c})
+			}
+
+			for _, ref := range conceptmap.FindConceptReferences(c.Description) {
+				if key := referencedKey(cmap, ref); key != c.Key() {
+					idx.backlinks[key] = appendDistinctConcept(idx.backlinks[key], &ConceptInMap{ConceptMap: cmap, Concept: c})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(idx.predicates, func(i, j int) bool {
+		return strings.ToLower(string(idx.predicates[i].Predicate)) < strings.ToLower(string(idx.predicates[j].Predicate))
+	})
+
+	sort.SliceStable(idx.tags, func(i, j int) bool {
+		return strings.ToLower(idx.tags[i].Tag) < strings.ToLower(idx.tags[j].Tag)
+	})
+
+	return idx
+}
+
+// ConceptMaps returns every concept map in the site
+func (idx *SiteIndex) ConceptMaps() []*conceptmap.ConceptMap {
+	return idx.conceptMaps
+}
+
+// Occurrences returns every concept map containing c, along with the concept as
+// it is defined in that map
+func (idx *SiteIndex) Occurrences(c *conceptmap.Concept) []*ConceptInMap {
+	return idx.occurrences[c.Key()]
+}
+
+// OtherOccurrences returns every concept map other than cmap that contains c
+func (idx *SiteIndex) OtherOccurrences(cmap *conceptmap.ConceptMap, c *conceptmap.Concept) []*ConceptInMap {
+	output := []*ConceptInMap{}
+
+	for _, o := range idx.occurrences[c.Key()] {
+		if o.ConceptMap != cmap {
+			output = append(output, o)
+		}
+	}
+
+	return output
+}
+
+// Backlinks returns the concepts, in any map, whose descriptions reference c
+func (idx *SiteIndex) Backlinks(c *conceptmap.Concept) []*ConceptInMap {
+	return idx.backlinks[c.Key()]
+}
+
+// MapsReferencing returns the concept maps whose descriptions reference c
+func (idx *SiteIndex) MapsReferencing(c *conceptmap.Concept) []*conceptmap.ConceptMap {
+	return idx.mapReferences[c.Key()]
+}
+
+// Predicates returns every distinct predicate used in the site, sorted
+// alphabetically
+func (idx *SiteIndex) Predicates() []*PredicateUsage {
+	return idx.predicates
+}
+
+// Tags returns every distinct concept tag used in the site, sorted alphabetically
+func (idx *SiteIndex) Tags() []*TagUsage {
+	return idx.tags
+}
+
+// referencedKey returns the key of the concept in cmap that ref refers to, which
+// differs from the key of the reference itself when it refers to an alias
+func referencedKey(cmap *conceptmap.ConceptMap, ref *conceptmap.ConceptReference) string {
+	if c := cmap.Concept(ref.Label); c != nil {
+		return c.Key()
+	}
+
+	return ref.Key()
+}
+
+func appendDistinctMap(cmaps []*conceptmap.ConceptMap, cmap *conceptmap.ConceptMap) []*conceptmap.ConceptMap {
+	for _, m := range cmaps {
+		if m == cmap {
+			return cmaps
+		}
+	}
+
+	return append(cmaps, cmap)
+}
+
+func appendDistinctConcept(cs []*ConceptInMap, c *ConceptInMap) []*ConceptInMap {
+	for _, o := range cs {
+		if o.ConceptMap == c.ConceptMap && o.Concept == c.Concept {
+			return cs
+		}
+	}
+
+	return append(cs, c)
+}
@@ -0,0 +1,131 @@
+package sitegenerator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+	"github.com/gosimple/slug"
+)
+
+// Names of the page templates. A template directory passed to LoadTemplates can
+// override any of these by providing a file with the same name
+const (
+	IndexTemplateName             = "index.md.tmpl"
+	ConceptMapSummaryTemplateName = "summary.md.tmpl"
+	ConceptMapDetailTemplateName  = "detail.md.tmpl"
+	ConceptTemplateName           = "concept.md.tmpl"
+	GlossaryTemplateName          = "glossary.md.tmpl"
+	PredicatesTemplateName        = "predicates.md.tmpl"
+	PredicateTemplateName         = "predicate.md.tmpl"
+	TagsTemplateName              = "tags.md.tmpl"
+	TagTemplateName               = "tag.md.tmpl"
+	ConceptMapPartTemplateName    = "part.md.tmpl"
+)
+
+// Templates is the set of text/templates used to render the pages of a site
+type Templates struct {
+	tpl *template.Template
+}
+
+// TemplateFuncs are the helper functions available to every template
+var TemplateFuncs = template.FuncMap{
+	// relLink returns the path of to relative to the directory containing from. Both
+	// paths are relative to the root of the site's content, e.g. {{ relLink .Page "index.md" }}.
+	// The result is used in links, so is always separated by forward slashes
+	"relLink": func(from, to string) (string, error) {
+		p, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.ToSlash(p), nil
+	},
+
+	// slugify converts s to a url friendly slug, the same way concept keys and map
+	// slugs are made
+	"slugify": func(s string) string {
+		return slug.Make(s)
+	},
+
+	// escape escapes markdown syntax in s, so that labels can be safely used in
+	// headings and link text
+	"escape": func(v interface{}) string {
+		return escapeMarkdown(fmt.Sprint(v))
+	},
+
+	// markdown renders a markdown description to be placed beneath a heading of the
+	// given level, demoting any headings it contains and linking [[Label]] style
+	// concept references to the concept's page in cmap
+	"markdown": renderDescription,
+
+	// propositionsFor returns all propositions in cmap that involve concept
+	"propositionsFor": func(cmap *conceptmap.ConceptMap, concept *conceptmap.Concept) conceptmap.PropositionList {
+		return cmap.Propositions.InvolvingConcepts(concept)
+	},
+}
+
+var defaultTemplates = mustParseDefaultTemplates()
+
+func mustParseDefaultTemplates() *Templates {
+	tpl := template.New("sitegenerator").Funcs(TemplateFuncs)
+
+	for name, src := range map[string]string{
+		IndexTemplateName:             indexPageTemplateSource,
+		ConceptMapSummaryTemplateName: conceptMapSummaryPageTemplateSource,
+		ConceptMapDetailTemplateName:  conceptMapDetailPageTemplateSource,
+		ConceptTemplateName:           conceptPageTemplateSource,
+		GlossaryTemplateName:          glossaryPageTemplateSource,
+		PredicatesTemplateName:        predicatesPageTemplateSource,
+		PredicateTemplateName:         predicatePageTemplateSource,
+		TagsTemplateName:              tagsPageTemplateSource,
+		TagTemplateName:               tagPageTemplateSource,
+		ConceptMapPartTemplateName:    conceptMapPartPageTemplateSource,
+	} {
+		template.Must(tpl.New(name).Parse(src))
+	}
+
+	return &Templates{tpl: tpl}
+}
+
+// DefaultTemplates returns the built in templates
+func DefaultTemplates() *Templates {
+	return defaultTemplates
+}
+
+// LoadTemplates returns the built in templates, overridden by every *.tmpl file
+// in dir. Files named after one of the page templates replace it, and any other
+// files are available as partials via {{ template "name.tmpl" . }}
+func LoadTemplates(dir string) (*Templates, error) {
+	tpl, err := defaultTemplates.tpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tpl.New(filepath.Base(file)).Parse(string(b)); err != nil {
+			return nil, fmt.Errorf("error parsing template %s: %w", file, err)
+		}
+	}
+
+	return &Templates{tpl: tpl}, nil
+}
+
+func (t *Templates) page(name string, data interface{}) PageTemplate {
+	return PageTemplateFunc(func(w io.Writer) error {
+		return t.tpl.ExecuteTemplate(w, name, data)
+	})
+}
@@ -1,39 +1,49 @@
 package sitegenerator
 
 import (
-	"io"
-	"text/template"
-
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
 )
 
-var conceptMapDetailPageTemplate = template.Must(template.New("concept-map-detail").Parse(`
-# Concept Map: {{.ConceptMap.Title}}
-{{.ConceptMap.Description}}
+const conceptMapDetailPageTemplateSource = `
+# Concept Map: {{ escape .ConceptMap.Title }}
+{{ markdown .Paths .ConceptMap .ConceptMap.Description 1 }}
 
-> This is a detailed view of this map. You might also like to [view a summary of the key concepts](summary.md).
+> This is a detailed view of this map. You might also like to [view a summary of the key concepts]({{ .Paths.ConceptMapSummaryMarkdownFile .ConceptMap }}).
+{{ if .Parts }}
+> This map is too large to draw in a single diagram, so it has been split into parts.
 
-## Diagram
-![{{.ConceptMap.Title}}]({{.Diagram}})
+## Overview
+![{{ escape .ConceptMap.Title }}]({{.Diagram}})
 
+## Parts {{ range .Parts }}
+### [{{ escape .Name }}]({{ $.Paths.ConceptMapPartMarkdownFile $.ConceptMap . }})
+{{ range $i, $c := .Concepts }}{{ if $i }}, {{ end }}[{{ escape $c.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap $c }}){{ end }}
+{{ end }}{{ else }}
+## Diagram
+![{{ escape .ConceptMap.Title }}]({{.Diagram}})
+{{ end }}
 ## Concepts {{ range .ConceptMap.Concepts }}{{ $c := . }}
-### [{{.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Key}}.md)
-{{.Description}}{{ range $.ConceptMap.Propositions.InvolvingConcepts . }}
-- {{ if (eq .Left.Key $c.Key) }}{{.Left.Label}} {{.Predicate}} [{{.Right.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Right.Key}}.md){{else}}[{{.Left.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Left.Key}}.md) {{.Predicate}} {{.Right.Label}}{{ end }}{{ end }}
+### [{{ escape .Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap . }})
+{{ markdown $.Paths $.ConceptMap .Description 3 }}{{ range propositionsFor $.ConceptMap . }}
+- {{ if (eq .Left.Key $c.Key) }}{{ escape .Left.Label }} {{ escape .Predicate }} [{{ escape .Right.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Right }}){{else}}[{{ escape .Left.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Left }}) {{ escape .Predicate }} {{ escape .Right.Label }}{{ end }}{{ end }}
 {{ end }}
-`))
-
-type conceptMapDetailPageTemplateData struct {
-	Diagram    string
-	ConceptMap *conceptmap.ConceptMap
-}
-
-func NewConceptMapDetailPageTemplate(conceptMap *conceptmap.ConceptMap, ph *FilePathHelper) PageTemplate {
-	return PageTemplateFunc(func(w io.Writer) error {
-		return conceptMapDetailPageTemplate.Execute(w, &conceptMapSummaryPageTemplateData{
-			Diagram:    ph.ConceptMapDetailImageFile(conceptMap),
-			ConceptMap: conceptMap,
-		})
+`
+
+// ConceptMapDetailPage returns the template for a concept map's detail page. If
+// the map has been split into parts, the page shows an overview of the parts
+// rather than a single detailed diagram
+func (t *Templates) ConceptMapDetailPage(page string, conceptMap *conceptmap.ConceptMap, parts []*ConceptMapPart, ph *FilePathHelper) PageTemplate {
+	diagram := ph.ConceptMapDetailImageFile(conceptMap)
+
+	if len(parts) > 0 {
+		diagram = ph.ConceptMapOverviewImageFile(conceptMap)
+	}
+
+	return t.page(ConceptMapDetailTemplateName, &ConceptMapPageData{
+		Page:       page,
+		Diagram:    diagram,
+		ConceptMap: conceptMap,
+		Parts:      parts,
+		Paths:      ph,
 	})
-
 }
@@ -0,0 +1,51 @@
+package sitegenerator
+
+import (
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+const conceptMapPartPageTemplateSource = `
+### Concept Map: [{{ escape .ConceptMap.Title }}]({{ .Paths.ConceptMapSummaryMarkdownFile .ConceptMap }})
+# {{ escape .Part.Name }}
+
+> This is one part of a map that is too large to draw in a single diagram. You might also like to [view an overview of all the parts]({{ .Paths.ConceptMapDetailMarkdownFile .ConceptMap }}).
+
+## Diagram
+![{{ escape .Part.Name }}]({{.Diagram}})
+
+## Concepts {{ range .Part.Concepts }}{{ $c := . }}
+### [{{ escape .Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap . }})
+{{ markdown $.Paths $.ConceptMap .Description 3 }}{{ range propositionsFor $.ConceptMap . }}
+- {{ if (eq .Left.Key $c.Key) }}{{ escape .Left.Label }} {{ escape .Predicate }} [{{ escape .Right.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Right }}){{else}}[{{ escape .Left.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Left }}) {{ escape .Predicate }} {{ escape .Right.Label }}{{ end }}{{ end }}
+{{ end }}
+`
+
+// ConceptMapPartPageData is the data passed to the concept map part page template
+type ConceptMapPartPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// Diagram is the link to the page's diagram
+	Diagram string
+
+	// ConceptMap is the concept map that Part belongs to
+	ConceptMap *conceptmap.ConceptMap
+
+	// Part is the part of ConceptMap the page describes
+	Part *ConceptMapPart
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
+}
+
+// ConceptMapPartPage returns the template for one part of a concept map that is
+// too large to draw in a single diagram
+func (t *Templates) ConceptMapPartPage(page string, conceptMap *conceptmap.ConceptMap, part *ConceptMapPart, ph *FilePathHelper) PageTemplate {
+	return t.page(ConceptMapPartTemplateName, &ConceptMapPartPageData{
+		Page:       page,
+		Diagram:    ph.ConceptMapPartImageFile(conceptMap, part),
+		ConceptMap: conceptMap,
+		Part:       part,
+		Paths:      ph,
+	})
+}
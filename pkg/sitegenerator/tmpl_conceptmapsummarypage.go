@@ -1,48 +1,66 @@
 package sitegenerator
 
 import (
-	"io"
-	"text/template"
-
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
 )
 
-var conceptMapSummaryPageTemplate = template.Must(template.New("concept-map-summary").Parse(`
-# Concept Map: {{.ConceptMap.Title}}
-{{.ConceptMap.Description}}
-{{ if .ConceptMap.HasKeyConcepts }}
+const conceptMapSummaryPageTemplateSource = `
+# Concept Map: {{ escape .ConceptMap.Title }}
+{{ markdown .Paths .ConceptMap .ConceptMap.Description 1 }}
+{{ if .Parts }}
+
+> This map is large, so you might also like to [view an overview of its parts]({{ .Paths.ConceptMapDetailMarkdownFile .ConceptMap }}).
+
+{{ else if .ConceptMap.HasKeyConcepts }}
 
-> This is a summary of the key concepts in this map. You might also like to [view the map in its entirety](detail.md).
+> This is a summary of the key concepts in this map. You might also like to [view the map in its entirety]({{ .Paths.ConceptMapDetailMarkdownFile .ConceptMap }}).
 
 {{ end }}
 ## Diagram
-![{{.ConceptMap.Title}}]({{.Diagram}})
+![{{ escape .ConceptMap.Title }}]({{.Diagram}})
 
 {{ if .ConceptMap.HasKeyConcepts }}
 ## Concepts {{ range .ConceptMap.KeyConcepts }}{{ $c := . }}
-### [{{.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Key}}.md)
-{{.Description}}{{ range $.ConceptMap.Propositions.InvolvingConcepts . }}
-- {{ if (eq .Left.Key $c.Key) }}{{.Left.Label}} {{.Predicate}} [{{.Right.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Right.Key}}.md){{else}}[{{.Left.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Left.Key}}.md) {{.Predicate}} {{.Right.Label}}{{ end }}{{ end }}
+### [{{ escape .Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap . }})
+{{ markdown $.Paths $.ConceptMap .Description 3 }}{{ range propositionsFor $.ConceptMap . }}
+- {{ if (eq .Left.Key $c.Key) }}{{ escape .Left.Label }} {{ escape .Predicate }} [{{ escape .Right.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Right }}){{else}}[{{ escape .Left.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Left }}) {{ escape .Predicate }} {{ escape .Right.Label }}{{ end }}{{ end }}
 {{ end }}
 {{ else }}
 ## Concepts {{ range .ConceptMap.Concepts }}{{ $c := . }}
-### [{{.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Key}}.md)
-{{.Description}}{{ range $.ConceptMap.Propositions.InvolvingConcepts . }}
-- {{ if (eq .Left.Key $c.Key) }}{{.Left.Label}} {{.Predicate}} [{{.Right.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Right.Key}}.md){{else}}[{{.Left.Label}}](../{{$.ConceptMap.Slug}}/concepts/{{.Left.Key}}.md) {{.Predicate}} {{.Right.Label}}{{ end }}{{ end }}
+### [{{ escape .Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap . }})
+{{ markdown $.Paths $.ConceptMap .Description 3 }}{{ range propositionsFor $.ConceptMap . }}
+- {{ if (eq .Left.Key $c.Key) }}{{ escape .Left.Label }} {{ escape .Predicate }} [{{ escape .Right.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Right }}){{else}}[{{ escape .Left.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Left }}) {{ escape .Predicate }} {{ escape .Right.Label }}{{ end }}{{ end }}
 {{ end }}
 {{ end}}
-`))
+`
 
-type conceptMapSummaryPageTemplateData struct {
-	Diagram    string
+// ConceptMapPageData is the data passed to the concept map summary and detail
+// page templates
+type ConceptMapPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// Diagram is the link to the page's diagram
+	Diagram string
+
+	// ConceptMap is the concept map the page describes
 	ConceptMap *conceptmap.ConceptMap
+
+	// Parts are the parts that ConceptMap has been split into, if it is too large
+	// to draw in a single detail diagram
+	Parts []*ConceptMapPart
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
 }
 
-func NewConceptMapSummaryPageTemplate(conceptMap *conceptmap.ConceptMap, ph *FilePathHelper) PageTemplate {
-	return PageTemplateFunc(func(w io.Writer) error {
-		return conceptMapSummaryPageTemplate.Execute(w, &conceptMapSummaryPageTemplateData{
-			Diagram:    ph.ConceptMapSummaryImageFile(conceptMap),
-			ConceptMap: conceptMap,
-		})
+// ConceptMapSummaryPage returns the template for a concept map's summary page
+func (t *Templates) ConceptMapSummaryPage(page string, conceptMap *conceptmap.ConceptMap, parts []*ConceptMapPart, ph *FilePathHelper) PageTemplate {
+	return t.page(ConceptMapSummaryTemplateName, &ConceptMapPageData{
+		Page:       page,
+		Diagram:    ph.ConceptMapSummaryImageFile(conceptMap),
+		ConceptMap: conceptMap,
+		Parts:      parts,
+		Paths:      ph,
 	})
 }
@@ -1,43 +1,81 @@
 package sitegenerator
 
 import (
-	"io"
-	"text/template"
-
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
 )
 
-var (
-	conceptPageTemplate = template.Must(template.New("concept").Parse(`
-### Concept Map: [{{.ConceptMap.Title}}](../../{{.ConceptMap.Slug}}/summary.md)
-# Concept: {{.Concept.Label}}
-{{.Concept.Description}}
+const conceptPageTemplateSource = `
+### Concept Map: [{{ escape .ConceptMap.Title }}]({{ .Paths.ConceptMapSummaryMarkdownFile .ConceptMap }})
+# Concept: {{ escape .Concept.Label }}
+{{ if .Concept.Tags }}
+Tags: {{ range $i, $t := .Concept.Tags }}{{ if $i }}, {{ end }}[{{ escape $t }}]({{ $.Paths.TagMarkdownFile $t }}){{ end }}
+
+{{ end }}{{ markdown .Paths .ConceptMap .Concept.Description 1 }}
 
 ## Diagram
-![{{.Concept.Label}}]({{.Diagram}})
+![{{ escape .Concept.Label }}]({{.Diagram}})
 
 ## Related Concepts {{ range .RelatedConcepts }}{{ $c := . }}
-### [{{.Label}}]({{.Key}}.md)
-{{.Description}}{{ range $.ConceptMap.Propositions.InvolvingConcepts . }}
-- {{ if (eq .Left.Key $c.Key) }}{{.Left.Label}} {{.Predicate}} [{{.Right.Label}}]({{.Right.Key}}.md){{else}}[{{.Left.Label}}]({{.Left.Key}}.md) {{.Predicate}} {{.Right.Label}}{{ end }}{{ end }}
+### [{{ escape .Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap . }})
+{{ markdown $.Paths $.ConceptMap .Description 3 }}{{ range propositionsFor $.ConceptMap . }}
+- {{ if (eq .Left.Key $c.Key) }}{{ escape .Left.Label }} {{ escape .Predicate }} [{{ escape .Right.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Right }}){{else}}[{{ escape .Left.Label }}]({{ $.Paths.ConceptMarkdownFile $.ConceptMap .Left }}) {{ escape .Predicate }} {{ escape .Right.Label }}{{ end }}{{ end }}
 {{ end }}
-`))
-)
+{{ if or .Backlinks .ReferencingMaps }}
+## Backlinks {{ range .ReferencingMaps }}
+- [{{ escape .Title }}]({{ $.Paths.ConceptMapSummaryMarkdownFile . }}){{ end }}{{ range .Backlinks }}
+- [{{ escape .Concept.Label }}]({{ $.Paths.ConceptMarkdownFile .ConceptMap .Concept }}){{ if ne .ConceptMap $.ConceptMap }} in {{ escape .ConceptMap.Title }}{{ end }}{{ end }}
+{{ end }}{{ if .MentionedIn }}
+## Mentioned In {{ range .MentionedIn }}
+- [{{ escape .ConceptMap.Title }}]({{ $.Paths.ConceptMarkdownFile .ConceptMap .Concept }}){{ end }}
+{{ end }}
+`
+
+// ConceptPageData is the data passed to the concept page template
+type ConceptPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// ConceptMap is the concept map that Concept belongs to
+	ConceptMap *conceptmap.ConceptMap
+
+	// Diagram is the link to the page's diagram
+	Diagram string
 
-type conceptPageTemplateData struct {
-	ConceptMap      *conceptmap.ConceptMap
-	Diagram         string
-	Concept         *conceptmap.Concept
+	// Concept is the concept the page describes
+	Concept *conceptmap.Concept
+
+	// RelatedConcepts are the concepts joined to Concept by a proposition
 	RelatedConcepts []*conceptmap.Concept
+
+	// Backlinks are the concepts, in any concept map, whose descriptions reference
+	// Concept
+	Backlinks []*ConceptInMap
+
+	// ReferencingMaps are the concept maps whose descriptions reference Concept
+	ReferencingMaps []*conceptmap.ConceptMap
+
+	// MentionedIn are the other concept maps that contain Concept
+	MentionedIn []*ConceptInMap
+
+	// Site indexes every concept map in the site
+	Site *SiteIndex
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
 }
 
-func NewConceptPageTemplate(conceptMap *conceptmap.ConceptMap, concept *conceptmap.Concept, ph *FilePathHelper) PageTemplate {
-	return PageTemplateFunc(func(w io.Writer) error {
-		return conceptPageTemplate.Execute(w, &conceptPageTemplateData{
-			ConceptMap:      conceptMap,
-			Concept:         concept,
-			Diagram:         ph.ConceptImageFile(conceptMap, concept),
-			RelatedConcepts: conceptMap.ConceptsRelatedTo(concept),
-		})
+// ConceptPage returns the template for a single concept's page
+func (t *Templates) ConceptPage(page string, conceptMap *conceptmap.ConceptMap, concept *conceptmap.Concept, site *SiteIndex, ph *FilePathHelper) PageTemplate {
+	return t.page(ConceptTemplateName, &ConceptPageData{
+		Page:            page,
+		ConceptMap:      conceptMap,
+		Concept:         concept,
+		Diagram:         ph.ConceptImageFile(conceptMap, concept),
+		RelatedConcepts: conceptMap.ConceptsRelatedTo(concept),
+		Backlinks:       site.Backlinks(concept),
+		ReferencingMaps: site.MapsReferencing(concept),
+		MentionedIn:     site.OtherOccurrences(conceptMap, concept),
+		Site:            site,
+		Paths:           ph,
 	})
 }
@@ -0,0 +1,131 @@
+package sitegenerator
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bernos/conceptmapper/pkg/conceptmap"
+)
+
+const glossaryPageTemplateSource = `
+# Glossary
+{{ range .Letters }}
+## {{ escape .Letter }}
+{{ range .Entries }}
+### {{ escape .Label }}
+{{ if .Description }}{{ markdown $.Paths .DescriptionMap .Description 3 }}{{ else }}> _No description_{{ end }}
+{{ range .Occurrences }}
+- [{{ escape .ConceptMap.Title }}]({{ $.Paths.ConceptMarkdownFile .ConceptMap .Concept }}){{ end }}
+{{ end }}{{ end }}
+`
+
+// GlossaryPageData is the data passed to the glossary page template
+type GlossaryPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// Letters groups every concept in the site by the first letter of its label
+	Letters []*GlossaryLetter
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
+}
+
+// GlossaryLetter is a group of glossary entries whose labels start with Letter
+type GlossaryLetter struct {
+	Letter  string
+	Entries []*GlossaryEntry
+}
+
+// GlossaryEntry describes a concept that appears in one or more concept maps
+type GlossaryEntry struct {
+	Label string
+
+	// Description is the first non empty description of the concept, or empty if no
+	// map describes it
+	Description string
+
+	// DescriptionMap is the concept map that Description was taken from
+	DescriptionMap *conceptmap.ConceptMap
+
+	// Occurrences are the concept maps that contain the concept
+	Occurrences []*ConceptInMap
+}
+
+// NewGlossary builds glossary entries for every concept in the site, grouped by
+// letter and sorted alphabetically
+func NewGlossary(site *SiteIndex) []*GlossaryLetter {
+	entries := []*GlossaryEntry{}
+	seen := map[string]bool{}
+
+	for _, cmap := range site.ConceptMaps() {
+		for _, c := range cmap.Concepts {
+			if seen[c.Key()] {
+				continue
+			}
+
+			seen[c.Key()] = true
+
+			entry := &GlossaryEntry{
+				Label:       c.Label,
+				Occurrences: site.Occurrences(c),
+			}
+
+			for _, o := range entry.Occurrences {
+				if strings.TrimSpace(o.Concept.Description) != "" {
+					entry.Description = o.Concept.Description
+					entry.DescriptionMap = o.ConceptMap
+					break
+				}
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		li, lj := glossaryLetter(entries[i].Label), glossaryLetter(entries[j].Label)
+		if li != lj {
+			return li < lj
+		}
+		return strings.ToLower(entries[i].Label) < strings.ToLower(entries[j].Label)
+	})
+
+	letters := []*GlossaryLetter{}
+
+	for _, entry := range entries {
+		letter := glossaryLetter(entry.Label)
+
+		if len(letters) == 0 || letters[len(letters)-1].Letter != letter {
+			letters = append(letters, &GlossaryLetter{Letter: letter})
+		}
+
+		l := letters[len(letters)-1]
+		l.Entries = append(l.Entries, entry)
+	}
+
+	return letters
+}
+
+// glossaryLetter returns the upper case first letter of label, or # if label
+// doesn't start with a letter
+func glossaryLetter(label string) string {
+	for _, r := range label {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToUpper(r))
+		}
+		break
+	}
+
+	return "#"
+}
+
+// GlossaryPage returns the template for the site's glossary page
+func (t *Templates) GlossaryPage(page string, site *SiteIndex, ph *FilePathHelper) PageTemplate {
+	return t.page(GlossaryTemplateName, &GlossaryPageData{
+		Page:    page,
+		Letters: NewGlossary(site),
+		Paths:   ph,
+	})
+}
@@ -1,27 +1,42 @@
 package sitegenerator
 
 import (
-	"io"
-	"text/template"
-
 	"github.com/bernos/conceptmapper/pkg/conceptmap"
 )
 
-var indexPageTemplate = template.Must(template.New("index").Parse(`
-# Concept Maps{{ range .ConceptMaps }}
-## [{{.Title}}](./{{.Slug}}/summary.md)
-{{.Description}}
+const indexPageTemplateSource = `
+# Concept Maps
+
+> See the [glossary]({{ .Paths.GlossaryMarkdownFile }}) for a list of every concept across all maps, or
+> the [predicates]({{ .Paths.PredicatesMarkdownFile }}) used to relate them.{{ if .Site.Tags }} Concepts are also
+> grouped by [tag]({{ .Paths.TagsMarkdownFile }}).{{ end }}
+{{ range .ConceptMaps }}
+## [{{ escape .Title }}]({{ $.Paths.ConceptMapSummaryMarkdownFile . }})
+{{ markdown $.Paths . .Description 2 }}
 {{end}}
-`))
+`
+
+// IndexPageData is the data passed to the index page template
+type IndexPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
 
-type indexPageTemplateData struct {
+	// ConceptMaps are all of the concept maps in the site
 	ConceptMaps []*conceptmap.ConceptMap
+
+	// Site indexes every concept map in the site
+	Site *SiteIndex
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
 }
 
-func NewIndexPageTemplate(conceptMaps []*conceptmap.ConceptMap) PageTemplate {
-	return PageTemplateFunc(func(w io.Writer) error {
-		return indexPageTemplate.Execute(w, &indexPageTemplateData{
-			ConceptMaps: conceptMaps,
-		})
+// IndexPage returns the template for the site's index page
+func (t *Templates) IndexPage(page string, site *SiteIndex, ph *FilePathHelper) PageTemplate {
+	return t.page(IndexTemplateName, &IndexPageData{
+		Page:        page,
+		ConceptMaps: site.ConceptMaps(),
+		Site:        site,
+		Paths:       ph,
 	})
 }
@@ -0,0 +1,34 @@
+package sitegenerator
+
+const predicatePageTemplateSource = `
+### [Predicates]({{ .Paths.PredicatesMarkdownFile }})
+# Predicate: {{ escape .Usage.Predicate }}
+
+Used by {{ .Usage.Count }} proposition{{ if ne .Usage.Count 1 }}s{{ end }}.
+{{ range .Usage.ConceptMaps }}{{ $m := . }}
+## [{{ escape .Title }}]({{ $.Paths.ConceptMapSummaryMarkdownFile . }})
+{{ range .Propositions.WithPredicate $.Usage.Predicate }}
+- [{{ escape .Left.Label }}]({{ $.Paths.ConceptMarkdownFile $m .Left }}) {{ escape .Predicate }} [{{ escape .Right.Label }}]({{ $.Paths.ConceptMarkdownFile $m .Right }}){{ end }}
+{{ end }}
+`
+
+// PredicatePageData is the data passed to the predicate page template
+type PredicatePageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// Usage describes every use of the predicate across the site
+	Usage *PredicateUsage
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
+}
+
+// PredicatePage returns the template for a single predicate's page
+func (t *Templates) PredicatePage(page string, usage *PredicateUsage, ph *FilePathHelper) PageTemplate {
+	return t.page(PredicateTemplateName, &PredicatePageData{
+		Page:  page,
+		Usage: usage,
+		Paths: ph,
+	})
+}
@@ -0,0 +1,30 @@
+package sitegenerator
+
+const predicatesPageTemplateSource = `
+# Predicates
+
+| Predicate | Propositions | Concept Maps |
+| --- | --- | --- |{{ range .Predicates }}
+| [{{ escape .Predicate }}]({{ $.Paths.PredicateMarkdownFile .Predicate }}) | {{ .Count }} | {{ range $i, $m := .ConceptMaps }}{{ if $i }}, {{ end }}[{{ escape $m.Title }}]({{ $.Paths.ConceptMapSummaryMarkdownFile $m }}){{ end }} |{{ end }}
+`
+
+// PredicatesPageData is the data passed to the predicate overview page template
+type PredicatesPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// Predicates describes every predicate used in the site, sorted alphabetically
+	Predicates []*PredicateUsage
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
+}
+
+// PredicatesPage returns the template for the predicate overview page
+func (t *Templates) PredicatesPage(page string, site *SiteIndex, ph *FilePathHelper) PageTemplate {
+	return t.page(PredicatesTemplateName, &PredicatesPageData{
+		Page:       page,
+		Predicates: site.Predicates(),
+		Paths:      ph,
+	})
+}
@@ -0,0 +1,33 @@
+package sitegenerator
+
+const tagPageTemplateSource = `
+### [Tags]({{ .Paths.TagsMarkdownFile }})
+# Tag: {{ escape .Usage.Tag }}
+{{ range .Usage.ConceptMaps }}{{ $m := . }}
+## [{{ escape .Title }}]({{ $.Paths.ConceptMapSummaryMarkdownFile . }})
+![{{ escape $.Usage.Tag }}]({{ $.Paths.TagImageFile . $.Usage.Tag }})
+{{ range $.Usage.Concepts }}{{ if eq .ConceptMap $m }}
+- [{{ escape .Concept.Label }}]({{ $.Paths.ConceptMarkdownFile $m .Concept }}){{ end }}{{ end }}
+{{ end }}
+`
+
+// TagPageData is the data passed to the tag page template
+type TagPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// Usage describes every concept tagged with the tag across the site
+	Usage *TagUsage
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
+}
+
+// TagPage returns the template for a single tag's page
+func (t *Templates) TagPage(page string, usage *TagUsage, ph *FilePathHelper) PageTemplate {
+	return t.page(TagTemplateName, &TagPageData{
+		Page:  page,
+		Usage: usage,
+		Paths: ph,
+	})
+}
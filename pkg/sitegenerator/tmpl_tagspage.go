@@ -0,0 +1,30 @@
+package sitegenerator
+
+const tagsPageTemplateSource = `
+# Tags
+
+| Tag | Concepts | Concept Maps |
+| --- | --- | --- |{{ range .Tags }}
+| [{{ escape .Tag }}]({{ $.Paths.TagMarkdownFile .Tag }}) | {{ len .Concepts }} | {{ range $i, $m := .ConceptMaps }}{{ if $i }}, {{ end }}[{{ escape $m.Title }}]({{ $.Paths.ConceptMapSummaryMarkdownFile $m }}){{ end }} |{{ end }}
+`
+
+// TagsPageData is the data passed to the tag overview page template
+type TagsPageData struct {
+	// Page is the path of the page being rendered, relative to the content root
+	Page string
+
+	// Tags describes every concept tag used in the site, sorted alphabetically
+	Tags []*TagUsage
+
+	// Paths builds links from this page to other pages and images in the site
+	Paths *FilePathHelper
+}
+
+// TagsPage returns the template for the tag overview page
+func (t *Templates) TagsPage(page string, site *SiteIndex, ph *FilePathHelper) PageTemplate {
+	return t.page(TagsTemplateName, &TagsPageData{
+		Page:  page,
+		Tags:  site.Tags(),
+		Paths: ph,
+	})
+}